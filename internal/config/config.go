@@ -16,14 +16,48 @@
 package config
 
 import (
+	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/KAnggara75/conflect/internal/helper"
 )
 
+// Version modes for the config response version field.
+const (
+	VersionModeCommit  = "commit"
+	VersionModeContent = "content"
+)
+
+// Array flattening modes for YAML/JSON lists.
+const (
+	ArrayFlattenRaw     = "raw"
+	ArrayFlattenIndexed = "indexed"
+)
+
+// Placeholder resolution modes for ${key} references in config values.
+const (
+	PlaceholdersOff   = "off"
+	PlaceholdersLeave = "leave"
+	PlaceholdersFail  = "fail"
+)
+
+// Property source naming modes.
+const (
+	SourceNamesFile = "file"
+	SourceNamesPath = "path"
+)
+
+// DefaultSecretKeys are the key fragments treated as secrets when SECRET_KEYS is unset.
+var DefaultSecretKeys = []string{"password", "passwd", "secret", "token", "credential", "apikey", "privatekey"}
+
+// DefaultSearchPath keeps profile config in one directory per profile at the branch root.
+const DefaultSearchPath = "{profile}"
+
 type Config struct {
 	Port          string
 	RepoPath      string
@@ -32,6 +66,17 @@ type Config struct {
 	Limit         int
 	Token         string
 	PullInterval  int
+	CacheMaxAge   int
+	VersionMode   string
+	CompressMin   int
+	StrictTypes   bool
+	ArrayFlatten  string
+	SearchPaths   []string
+	SharedPaths   []string
+	Placeholders  string
+	SourceNames   string
+	SecretKeys    []string
+	ScopedTokens  map[string][]string
 }
 
 func Load() *Config {
@@ -49,9 +94,39 @@ func Load() *Config {
 		DefaultBranch: getEnv("DEFAULT_BRANCH", "main"),
 		Token:         readValue("APP_AUTH_SECRET", "APP_AUTH_SECRET_FILE", ""),
 		PullInterval:  getEnvInt("PULL_INTERVAL", 0),
+		CacheMaxAge:   getEnvInt("CACHE_MAX_AGE", 0),
+		VersionMode:   getEnvEnum("VERSION_MODE", VersionModeCommit, VersionModeContent),
+		CompressMin:   getEnvInt("COMPRESS_MIN_SIZE", 1024),
+		StrictTypes:   getEnvBool("STRICT_TYPES", false),
+		ArrayFlatten:  getEnvEnum("ARRAY_FLATTEN", ArrayFlattenRaw, ArrayFlattenIndexed),
+		SearchPaths:   getEnvList("SEARCH_PATHS", []string{DefaultSearchPath}),
+		SharedPaths:   getEnvList("SHARED_PATHS", nil),
+		Placeholders:  getEnvEnum("PLACEHOLDERS", PlaceholdersOff, PlaceholdersLeave, PlaceholdersFail),
+		SourceNames:   getEnvEnum("SOURCE_NAMES", SourceNamesFile, SourceNamesPath),
+		SecretKeys:    getEnvList("SECRET_KEYS", DefaultSecretKeys),
+		ScopedTokens:  parseScopedTokens(readValue("SCOPED_TOKENS", "SCOPED_TOKENS_FILE", "")),
 	}
 }
 
+// parseScopedTokens reads "<token> <pattern>[,<pattern>...]" entries separated by
+// newlines or semicolons. Patterns are {application}/{profile}/{label} globs; missing
+// segments match anything. Entries without patterns are ignored.
+func parseScopedTokens(value string) map[string][]string {
+	tokens := make(map[string][]string)
+	for _, line := range strings.FieldsFunc(value, func(r rune) bool { return r == '\n' || r == ';' }) {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for _, pattern := range strings.Split(strings.Join(fields[1:], ""), ",") {
+			if pattern = strings.TrimSpace(pattern); pattern != "" {
+				tokens[fields[0]] = append(tokens[fields[0]], pattern)
+			}
+		}
+	}
+	return tokens
+}
+
 func buildRepoURL() string {
 	token := readValue("GIT_AUTH_TOKEN", "GIT_AUTH_TOKEN_FILE", "")
 	repoURL := readValue("REPO_URL", "REPO_URL_FILE", "")
@@ -92,3 +167,48 @@ func getEnvInt(key string, fallback int) int {
 	}
 	return i
 }
+
+func getEnvBool(key string, fallback bool) bool {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return fallback
+	}
+	return b
+}
+
+// getEnvEnum reads a case-insensitive setting that must be fallback or one of others.
+// An unknown value stops the server rather than silently picking the default.
+func getEnvEnum(key, fallback string, others ...string) string {
+	value, err := parseEnum(key, getEnv(key, fallback), append([]string{fallback}, others...))
+	if err != nil {
+		log.Fatalf("invalid config: %v", err)
+	}
+	return value
+}
+
+// parseEnum lower-cases value and checks it against allowed.
+func parseEnum(key, value string, allowed []string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if !slices.Contains(allowed, value) {
+		return "", fmt.Errorf("%s=%q, expected one of %s", key, value, strings.Join(allowed, ", "))
+	}
+	return value, nil
+}
+
+// getEnvList splits a comma-separated env var into its trimmed, non-empty items.
+func getEnvList(key string, fallback []string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	if len(list) == 0 {
+		return fallback
+	}
+	return list
+}
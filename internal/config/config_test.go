@@ -14,6 +14,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -113,6 +114,35 @@ func TestGetEnvInt(t *testing.T) {
 	}
 }
 
+func TestGetEnvBool(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		fallback bool
+		expected bool
+	}{
+		{name: "True value", envValue: "true", fallback: false, expected: true},
+		{name: "Numeric false", envValue: "0", fallback: true, expected: false},
+		{name: "Invalid value", envValue: "maybe", fallback: true, expected: true},
+		{name: "Empty value", envValue: "", fallback: true, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer os.Unsetenv("TEST_BOOL")
+
+			if tt.envValue != "" {
+				os.Setenv("TEST_BOOL", tt.envValue)
+			}
+
+			result := getEnvBool("TEST_BOOL", tt.fallback)
+			if result != tt.expected {
+				t.Errorf("getEnvBool(%q, %v) = %v, want %v", "TEST_BOOL", tt.fallback, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestReadValue(t *testing.T) {
 	// Create a temporary file for testing
 	tmpDir := t.TempDir()
@@ -222,6 +252,20 @@ func TestLoad(t *testing.T) {
 	os.Setenv("RATE_LIMIT", "20")
 	os.Setenv("DEFAULT_BRANCH", "develop")
 	os.Setenv("PULL_INTERVAL", "60")
+	os.Setenv("CACHE_MAX_AGE", "30")
+	defer os.Unsetenv("CACHE_MAX_AGE")
+	os.Setenv("VERSION_MODE", "Content")
+	defer os.Unsetenv("VERSION_MODE")
+	os.Setenv("SHARED_PATHS", "shared, teams/payments ,")
+	defer os.Unsetenv("SHARED_PATHS")
+	os.Setenv("SEARCH_PATHS", "services/{application}/{profile},{profile}")
+	defer os.Unsetenv("SEARCH_PATHS")
+	os.Setenv("PLACEHOLDERS", "Fail")
+	defer os.Unsetenv("PLACEHOLDERS")
+	os.Setenv("SECRET_KEYS", "password,pin")
+	defer os.Unsetenv("SECRET_KEYS")
+	os.Setenv("SCOPED_TOKENS", "ci-token orders/*/main, billing/prod;ops-token *")
+	defer os.Unsetenv("SCOPED_TOKENS")
 
 	cfg := Load()
 
@@ -241,6 +285,35 @@ func TestLoad(t *testing.T) {
 		t.Errorf("Load() PullInterval = %d, want 60", cfg.PullInterval)
 	}
 
+	if cfg.CacheMaxAge != 30 {
+		t.Errorf("Load() CacheMaxAge = %d, want 30", cfg.CacheMaxAge)
+	}
+
+	if cfg.VersionMode != VersionModeContent {
+		t.Errorf("Load() VersionMode = %s, want %s", cfg.VersionMode, VersionModeContent)
+	}
+
+	if want := []string{"password", "pin"}; !reflect.DeepEqual(cfg.SecretKeys, want) {
+		t.Errorf("Load() SecretKeys = %v, want %v", cfg.SecretKeys, want)
+	}
+
+	if cfg.Placeholders != PlaceholdersFail {
+		t.Errorf("Load() Placeholders = %s, want %s", cfg.Placeholders, PlaceholdersFail)
+	}
+
+	wantScopes := map[string][]string{"ci-token": {"orders/*/main", "billing/prod"}, "ops-token": {"*"}}
+	if !reflect.DeepEqual(cfg.ScopedTokens, wantScopes) {
+		t.Errorf("Load() ScopedTokens = %v, want %v", cfg.ScopedTokens, wantScopes)
+	}
+
+	if want := []string{"services/{application}/{profile}", "{profile}"}; !reflect.DeepEqual(cfg.SearchPaths, want) {
+		t.Errorf("Load() SearchPaths = %v, want %v", cfg.SearchPaths, want)
+	}
+
+	if want := []string{"shared", "teams/payments"}; !reflect.DeepEqual(cfg.SharedPaths, want) {
+		t.Errorf("Load() SharedPaths = %v, want %v", cfg.SharedPaths, want)
+	}
+
 	if cfg.RepoPath == "" {
 		t.Error("Load() RepoPath should not be empty")
 	}
@@ -252,6 +325,14 @@ func TestLoadDefaults(t *testing.T) {
 	os.Unsetenv("RATE_LIMIT")
 	os.Unsetenv("DEFAULT_BRANCH")
 	os.Unsetenv("PULL_INTERVAL")
+	os.Unsetenv("CACHE_MAX_AGE")
+	os.Unsetenv("VERSION_MODE")
+	os.Unsetenv("COMPRESS_MIN_SIZE")
+	os.Unsetenv("ARRAY_FLATTEN")
+	os.Unsetenv("SHARED_PATHS")
+	os.Unsetenv("SEARCH_PATHS")
+	os.Unsetenv("PLACEHOLDERS")
+	os.Unsetenv("SECRET_KEYS")
 	os.Unsetenv("REPO_URL")
 	os.Unsetenv("REPO_PATH")
 
@@ -272,4 +353,62 @@ func TestLoadDefaults(t *testing.T) {
 	if cfg.PullInterval != 0 {
 		t.Errorf("Load() default PullInterval = %d, want 0", cfg.PullInterval)
 	}
+
+	if cfg.CacheMaxAge != 0 {
+		t.Errorf("Load() default CacheMaxAge = %d, want 0", cfg.CacheMaxAge)
+	}
+
+	if cfg.VersionMode != VersionModeCommit {
+		t.Errorf("Load() default VersionMode = %s, want %s", cfg.VersionMode, VersionModeCommit)
+	}
+
+	if !reflect.DeepEqual(cfg.SecretKeys, DefaultSecretKeys) {
+		t.Errorf("Load() default SecretKeys = %v, want %v", cfg.SecretKeys, DefaultSecretKeys)
+	}
+
+	if cfg.Placeholders != PlaceholdersOff {
+		t.Errorf("Load() default Placeholders = %s, want %s", cfg.Placeholders, PlaceholdersOff)
+	}
+
+	if len(cfg.ScopedTokens) != 0 {
+		t.Errorf("Load() default ScopedTokens = %v, want none", cfg.ScopedTokens)
+	}
+
+	if want := []string{DefaultSearchPath}; !reflect.DeepEqual(cfg.SearchPaths, want) {
+		t.Errorf("Load() default SearchPaths = %v, want %v", cfg.SearchPaths, want)
+	}
+
+	if len(cfg.SharedPaths) != 0 {
+		t.Errorf("Load() default SharedPaths = %v, want none", cfg.SharedPaths)
+	}
+
+	if cfg.ArrayFlatten != ArrayFlattenRaw {
+		t.Errorf("Load() default ArrayFlatten = %s, want %s", cfg.ArrayFlatten, ArrayFlattenRaw)
+	}
+
+	if cfg.CompressMin != 1024 {
+		t.Errorf("Load() default CompressMin = %d, want 1024", cfg.CompressMin)
+	}
+}
+
+func TestParseScopedTokens(t *testing.T) {
+	value := "# token scopes\nci-token orders/*/main,billing\n\nbroken-token\nops-token */prod/*\n"
+	want := map[string][]string{
+		"ci-token":  {"orders/*/main", "billing"},
+		"ops-token": {"*/prod/*"},
+	}
+	if got := parseScopedTokens(value); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseScopedTokens() = %v, want %v", got, want)
+	}
+}
+
+func TestParseEnum(t *testing.T) {
+	allowed := []string{VersionModeCommit, VersionModeContent}
+
+	if got, err := parseEnum("VERSION_MODE", " Content ", allowed); err != nil || got != VersionModeContent {
+		t.Errorf("parseEnum() = %q, %v, want %q", got, err, VersionModeContent)
+	}
+	if _, err := parseEnum("VERSION_MODE", "contents", allowed); err == nil {
+		t.Error("parseEnum() expected an error for an unknown value")
+	}
 }
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect http
+ */
+
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/delivery/http/middleware"
+	"github.com/KAnggara75/conflect/internal/errors"
+)
+
+// Limits of the batch endpoint.
+const (
+	maxBatchItems = 100
+	maxBatchBody  = 1 << 20
+)
+
+// handleBatch serves POST /api/batch: the configs of several applications in one
+// request. Each item gets the status the config endpoint would have answered with.
+func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		errors.HttpError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req dto.BatchRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		errors.HttpError(w, "invalid batch request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(req.Requests) == 0 || len(req.Requests) > maxBatchItems {
+		errors.HttpError(w, fmt.Sprintf("expected 1 to %d requests", maxBatchItems), http.StatusBadRequest)
+		return
+	}
+
+	opts, err := loadOptionsFromQuery(r)
+	if err != nil {
+		errors.HttpError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	scope := middleware.ScopeFromContext(r.Context())
+	batch := s.configService.NewBatch(opts)
+	resp := dto.BatchResponse{Results: make([]dto.BatchResult, len(req.Requests))}
+	for i, item := range req.Requests {
+		env := strings.Join(item.Profiles, ",")
+		// check the profiles the load will use, as an element may hold several
+		profiles := strings.Split(env, ",")
+		label := item.Label
+		if label == "" {
+			label = s.cfg.DefaultBranch
+		}
+
+		switch {
+		case item.App == "" || slices.ContainsFunc(profiles, isBlank):
+			resp.Results[i] = dto.BatchResult{Status: http.StatusBadRequest, Error: "app and profiles are required"}
+		case scope != nil && !scopeAllows(scope, item.App, profiles, label):
+			resp.Results[i] = dto.BatchResult{Status: http.StatusForbidden, Error: "access denied for " + item.App + " with env " + env + " on " + label}
+		default:
+			resp.Results[i] = batchResult(batch.Load(item.App, env, label), item.App, env)
+		}
+	}
+
+	writeJSON(w, resp)
+}
+
+// isBlank reports whether s is empty or only whitespace.
+func isBlank(s string) bool {
+	return strings.TrimSpace(s) == ""
+}
+
+// batchResult maps a loaded config to the status of the config endpoint.
+func batchResult(config *dto.ConfigResponse, appName, env string) dto.BatchResult {
+	switch {
+	case config.Error != "":
+		return dto.BatchResult{Status: http.StatusUnprocessableEntity, Config: config, Error: config.Error}
+	case len(config.PropertySources) == 0:
+		return dto.BatchResult{Status: http.StatusNotFound, Error: "config for " + appName + " with env " + env + " not found"}
+	default:
+		return dto.BatchResult{Status: http.StatusOK, Config: config}
+	}
+}
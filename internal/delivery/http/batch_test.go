@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/delivery/http/middleware"
+	"github.com/KAnggara75/conflect/internal/repository"
+	"github.com/KAnggara75/conflect/internal/service"
+)
+
+func TestHandleBatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	envDir := filepath.Join(tmpDir, "main", "prod")
+	_ = os.MkdirAll(envDir, 0755)
+	_ = os.WriteFile(filepath.Join(envDir, "orders-prod.yaml"), []byte("name: orders\n"), 0644)
+	_ = os.WriteFile(filepath.Join(envDir, "billing-prod.yaml"), []byte("name: billing\n"), 0644)
+	_ = os.WriteFile(filepath.Join(envDir, "application.yaml"), []byte("region: eu\n"), 0644)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := service.NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+	srv := &Server{cfg: cfg, configService: cs}
+
+	body := `{"requests":[
+		{"app":"orders","profiles":["prod"],"label":"main"},
+		{"app":"billing","profiles":["prod"]},
+		{"app":"unknown","profiles":["dev"]},
+		{"app":"orders","profiles":[]},
+		{"app":"payments","profiles":["prod"]}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/batch", strings.NewReader(body))
+	req = req.WithContext(middleware.WithScope(req.Context(), middleware.Scope{"orders", "billing", "unknown"}))
+	rec := httptest.NewRecorder()
+	srv.handleBatch(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp dto.BatchResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	wantStatus := []int{http.StatusOK, http.StatusOK, http.StatusNotFound, http.StatusBadRequest, http.StatusForbidden}
+	if len(resp.Results) != len(wantStatus) {
+		t.Fatalf("expected %d results, got %+v", len(wantStatus), resp.Results)
+	}
+	for i, want := range wantStatus {
+		if resp.Results[i].Status != want {
+			t.Errorf("result %d: expected status %d, got %+v", i, want, resp.Results[i])
+		}
+	}
+	if billing := resp.Results[1].Config; billing == nil || billing.Label != "main" || billing.PropertySources[0].Source["name"] != "billing" {
+		t.Errorf("unexpected billing config %+v", billing)
+	}
+
+	t.Run("Empty profile without scope", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/batch", strings.NewReader(`{"requests":[{"app":"orders","profiles":["prod",""]}]}`))
+		rec := httptest.NewRecorder()
+		srv.handleBatch(rec, req)
+
+		var resp dto.BatchResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(resp.Results) != 1 || resp.Results[0].Status != http.StatusBadRequest {
+			t.Errorf("expected a 400 result, got %+v", resp.Results)
+		}
+	})
+
+	t.Run("Several profiles in one element", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/batch", strings.NewReader(`{"requests":[{"app":"orders","profiles":["prod,dev"]}]}`))
+		req = req.WithContext(middleware.WithScope(req.Context(), middleware.Scope{"orders/p*/main"}))
+		rec := httptest.NewRecorder()
+		srv.handleBatch(rec, req)
+
+		var resp dto.BatchResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(resp.Results) != 1 || resp.Results[0].Status != http.StatusForbidden {
+			t.Errorf("expected a 403 result, got %+v", resp.Results)
+		}
+	})
+
+	for name, tt := range map[string]struct {
+		method string
+		body   string
+		code   int
+	}{
+		"Wrong method":  {http.MethodGet, "", http.StatusMethodNotAllowed},
+		"Invalid JSON":  {http.MethodPost, `{"requests":`, http.StatusBadRequest},
+		"Unknown field": {http.MethodPost, `{"apps":[]}`, http.StatusBadRequest},
+		"Empty batch":   {http.MethodPost, `{"requests":[]}`, http.StatusBadRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/batch", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			srv.handleBatch(rec, req)
+			if rec.Code != tt.code {
+				t.Errorf("expected %d, got %d: %s", tt.code, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect http
+ * https://github.com/KAnggara75/conflect/tree/main/internal/delivery/http
+ */
+
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+)
+
+// configETag derives a strong ETag from the resolved version, the requested
+// app/profiles/label and the request variant (e.g. the normalized query string).
+// It returns "" when the response has no version to derive from. The compression
+// middleware tells encoded bodies apart by appending the content-coding.
+func configETag(resp *dto.ConfigResponse, variant string) string {
+	if resp == nil || resp.Version == "" {
+		return ""
+	}
+
+	h := sha256.New()
+	for _, part := range []string{resp.Version, resp.Name, strings.Join(resp.Profiles, ","), resp.Label, variant} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// contentETag derives a strong ETag from a response body.
+func contentETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag,
+// using the weak comparison required for GET and HEAD requests.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" || etag == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// setCacheHeaders writes the ETag and Cache-Control headers for a config response.
+func (s *Server) setCacheHeaders(w http.ResponseWriter, etag string) {
+	maxAge := 0
+	if s.cfg != nil && s.cfg.CacheMaxAge > 0 {
+		maxAge = s.cfg.CacheMaxAge
+	}
+
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	w.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(maxAge))
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect http
+ */
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/repository"
+	"github.com/KAnggara75/conflect/internal/service"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestConfigETag(t *testing.T) {
+	base := &dto.ConfigResponse{Name: "myapp", Profiles: []string{"prod"}, Label: "main", Version: "abc"}
+
+	if got := configETag(&dto.ConfigResponse{Name: "myapp"}, ""); got != "" {
+		t.Errorf("expected empty ETag without version, got %q", got)
+	}
+
+	etag := configETag(base, "")
+	if etag == "" || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		t.Fatalf("expected quoted ETag, got %q", etag)
+	}
+
+	variants := []*dto.ConfigResponse{
+		{Name: "myapp", Profiles: []string{"prod"}, Label: "main", Version: "def"},
+		{Name: "other", Profiles: []string{"prod"}, Label: "main", Version: "abc"},
+		{Name: "myapp", Profiles: []string{"dev"}, Label: "main", Version: "abc"},
+		{Name: "myapp", Profiles: []string{"prod"}, Label: "develop", Version: "abc"},
+	}
+	for _, v := range variants {
+		if configETag(v, "") == etag {
+			t.Errorf("expected ETag for %+v to differ from base", v)
+		}
+	}
+
+	if configETag(base, "flatten=indexed") == etag {
+		t.Error("expected ETag to differ per request variant")
+	}
+}
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		etag   string
+		want   bool
+	}{
+		{`"abc"`, `"abc"`, true},
+		{`W/"abc"`, `"abc"`, true},
+		{`"xyz", "abc"`, `"abc"`, true},
+		{`*`, `"abc"`, true},
+		{`"xyz"`, `"abc"`, false},
+		{``, `"abc"`, false},
+		{`"abc"`, ``, false},
+	}
+
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, tt.etag); got != tt.want {
+			t.Errorf("etagMatches(%q, %q) = %v, want %v", tt.header, tt.etag, got, tt.want)
+		}
+	}
+}
+
+func TestHandleConfig_ConditionalGet(t *testing.T) {
+	tmpDir := t.TempDir()
+	mainDir := filepath.Join(tmpDir, "main")
+	envDir := filepath.Join(mainDir, "prod")
+	_ = os.MkdirAll(envDir, 0755)
+	_ = os.WriteFile(filepath.Join(envDir, "myapp-prod.yaml"), []byte("key: value"), 0644)
+
+	gitRepo, err := git.PlainInit(mainDir, false)
+	if err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+	wt, _ := gitRepo.Worktree()
+	_, _ = wt.Add("prod/myapp-prod.yaml")
+	_, _ = wt.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	})
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main", CacheMaxAge: 30}
+	repo := repository.NewGitRepo(tmpDir, "")
+	cs := service.NewConfigServiceFromRepo(repo, cfg)
+	srv := &Server{cfg: cfg, configService: cs}
+
+	req := httptest.NewRequest(http.MethodGet, "/myapp/prod/main", nil)
+	rec := httptest.NewRecorder()
+	srv.handleConfig(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag header on config response")
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != "private, max-age=30" {
+		t.Errorf("expected Cache-Control 'private, max-age=30', got %q", cc)
+	}
+
+	t.Run("Matching If-None-Match", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/myapp/prod/main", nil)
+		req.Header.Set("If-None-Match", etag)
+		rec := httptest.NewRecorder()
+		srv.handleConfig(rec, req)
+
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("expected 304, got %d", rec.Code)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("expected empty body on 304, got %q", rec.Body.String())
+		}
+		if rec.Header().Get("ETag") != etag {
+			t.Errorf("expected ETag %q on 304, got %q", etag, rec.Header().Get("ETag"))
+		}
+	})
+
+	t.Run("Stale If-None-Match", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/myapp/prod/main", nil)
+		req.Header.Set("If-None-Match", `"stale"`)
+		rec := httptest.NewRecorder()
+		srv.handleConfig(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected 200, got %d", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect http
+ */
+
+package http
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/KAnggara75/conflect/internal/errors"
+	"github.com/KAnggara75/conflect/internal/service"
+)
+
+// handleDiff serves GET /api/diff/{app}: the effective keys that differ between two
+// refs. `from` and `to` are a label, `label@commit` or `@commit` (on `label`);
+// `env` applies to both sides unless `fromEnv` or `toEnv` overrides it.
+func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request) {
+	appName := strings.TrimPrefix(r.URL.Path, "/api/diff/")
+	if appName == "" || strings.Contains(appName, "/") {
+		http.Error(w, `{"error":"invalid path, expected /api/diff/{app}"}`, http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	from := parseConfigRef(query.Get("from"), query.Get("label"), firstNonEmpty(query.Get("fromEnv"), query.Get("env")))
+	to := parseConfigRef(query.Get("to"), query.Get("label"), firstNonEmpty(query.Get("toEnv"), query.Get("env")))
+	if from.Env == "" || to.Env == "" {
+		errors.HttpError(w, "env, or fromEnv and toEnv, is required", http.StatusBadRequest)
+		return
+	}
+	if !s.authorize(w, r, appName, from.Env, from.Label) || !s.authorize(w, r, appName, to.Env, to.Label) {
+		return
+	}
+	if from == to {
+		errors.HttpError(w, "from and to select the same config", http.StatusBadRequest)
+		return
+	}
+
+	opts, err := loadOptionsFromQuery(r)
+	if err != nil {
+		errors.HttpError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	diff, err := s.configService.DiffConfig(appName, from, to, opts)
+	switch {
+	case stderrors.Is(err, errors.ErrNotFound):
+		errors.HttpError(w, err.Error(), http.StatusNotFound)
+		return
+	case stderrors.Is(err, errors.ErrInvalidInput):
+		errors.HttpError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	case err != nil:
+		log.Println(err)
+		errors.HttpError(w, "failed to diff config", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(diff)
+}
+
+// parseConfigRef reads label, label@commit or @commit, falling back to defaultLabel.
+func parseConfigRef(ref, defaultLabel, env string) service.ConfigRef {
+	label, commit, _ := strings.Cut(ref, "@")
+	if label == "" {
+		label = defaultLabel
+	}
+	return service.ConfigRef{Env: env, Label: label, Commit: commit}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
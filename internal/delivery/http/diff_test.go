@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/repository"
+	"github.com/KAnggara75/conflect/internal/service"
+)
+
+func TestHandleDiff(t *testing.T) {
+	tmpDir := t.TempDir()
+	for label, content := range map[string]string{
+		"main":    "db:\n  host: b\n  password: new\n",
+		"release": "db:\n  host: a\n  password: old\nlog: info\n",
+	} {
+		envDir := filepath.Join(tmpDir, label, "prod")
+		_ = os.MkdirAll(envDir, 0755)
+		_ = os.WriteFile(filepath.Join(envDir, "myapp-prod.yaml"), []byte(content), 0644)
+	}
+	_ = os.MkdirAll(filepath.Join(tmpDir, "main", "staging"), 0755)
+	_ = os.WriteFile(filepath.Join(tmpDir, "main", "staging", "myapp-staging.yaml"), []byte("db:\n  host: b\n"), 0644)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := service.NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+	srv := &Server{cfg: cfg, configService: cs}
+
+	t.Run("Labels", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/diff/myapp?env=prod&from=release&to=main", nil)
+		rec := httptest.NewRecorder()
+		srv.handleDiff(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp dto.DiffResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.From.Label != "release" || resp.To.Label != "main" {
+			t.Errorf("unexpected sides %+v -> %+v", resp.From, resp.To)
+		}
+		if resp.Removed["log"] != "info" || len(resp.Added) != 0 {
+			t.Errorf("unexpected added/removed %v / %v", resp.Added, resp.Removed)
+		}
+		if got := resp.Changed["db.password"]; got.From != service.MaskedValue || got.To != service.MaskedValue {
+			t.Errorf("expected masked password change, got %+v", got)
+		}
+	})
+
+	t.Run("Envs", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/diff/myapp?fromEnv=staging&toEnv=prod", nil)
+		rec := httptest.NewRecorder()
+		srv.handleDiff(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp dto.DiffResponse
+		_ = json.NewDecoder(rec.Body).Decode(&resp)
+		if resp.Added["db.password"] != service.MaskedValue || len(resp.Changed) != 0 {
+			t.Errorf("unexpected diff %+v", resp)
+		}
+	})
+
+	for name, tt := range map[string]struct {
+		path string
+		code int
+	}{
+		"Missing env":    {"/api/diff/myapp?from=release&to=main", http.StatusBadRequest},
+		"Same ref":       {"/api/diff/myapp?env=prod&from=main&to=main", http.StatusBadRequest},
+		"Invalid path":   {"/api/diff/myapp/prod?env=prod", http.StatusBadRequest},
+		"Unknown commit": {"/api/diff/myapp?env=prod&from=main@deadbeef&to=main", http.StatusNotFound},
+		"Unknown app":    {"/api/diff/unknown?env=prod&from=release&to=main", http.StatusNotFound},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+			srv.handleDiff(rec, req)
+			if rec.Code != tt.code {
+				t.Errorf("expected %d, got %d: %s", tt.code, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect http
+ */
+
+package http
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/delivery/http/middleware"
+	"github.com/KAnggara75/conflect/internal/errors"
+)
+
+// handleLabels serves the discovery endpoints, filtered by the caller's scope:
+//
+//	GET /api/labels
+//	GET /api/labels/{label}/envs
+//	GET /api/labels/{label}/envs/{env}/apps
+func (s *Server) handleLabels(w http.ResponseWriter, r *http.Request) {
+	scope := middleware.ScopeFromContext(r.Context())
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/labels"), "/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "":
+		labels, err := s.configService.ListLabels()
+		if err != nil {
+			log.Println(err)
+			errors.HttpError(w, "failed to list labels", http.StatusInternalServerError)
+			return
+		}
+
+		resp := dto.LabelsResponse{Labels: []string{}}
+		for _, label := range labels {
+			if scope.Allows("", "", label) {
+				resp.Labels = append(resp.Labels, label)
+			}
+		}
+		writeJSON(w, resp)
+
+	case len(parts) == 2 && parts[1] == "envs":
+		label := parts[0]
+		envs, ok := s.listApplications(w, scope, label)
+		if !ok {
+			return
+		}
+
+		resp := dto.EnvironmentsResponse{Label: label, Environments: []string{}}
+		for env := range envs {
+			if scope.Allows("", env, label) {
+				resp.Environments = append(resp.Environments, env)
+			}
+		}
+		sort.Strings(resp.Environments)
+		writeJSON(w, resp)
+
+	case len(parts) == 4 && parts[1] == "envs" && parts[3] == "apps":
+		label, env := parts[0], parts[2]
+		envs, ok := s.listApplications(w, scope, label)
+		if !ok {
+			return
+		}
+
+		apps, found := envs[env]
+		if !found || !scope.Allows("", env, label) {
+			errors.HttpError(w, "environment "+env+" not found on "+label, http.StatusNotFound)
+			return
+		}
+
+		resp := dto.ApplicationsResponse{Label: label, Environment: env, Applications: []string{}}
+		for _, app := range apps {
+			if scope.Allows(app, env, label) {
+				resp.Applications = append(resp.Applications, app)
+			}
+		}
+		writeJSON(w, resp)
+
+	default:
+		http.Error(w, `{"error":"invalid path, expected /api/labels, /api/labels/{label}/envs or /api/labels/{label}/envs/{env}/apps"}`, http.StatusBadRequest)
+	}
+}
+
+// listApplications loads the environments and applications of label, answering 404
+// for labels that do not exist or are outside the caller's scope.
+func (s *Server) listApplications(w http.ResponseWriter, scope middleware.Scope, label string) (map[string][]string, bool) {
+	if label == "" {
+		errors.HttpError(w, "label is required", http.StatusBadRequest)
+		return nil, false
+	}
+	if !scope.Allows("", "", label) {
+		errors.HttpError(w, "label "+label+" not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	envs, err := s.configService.ListApplications(label)
+	switch {
+	case stderrors.Is(err, errors.ErrInvalidInput):
+		errors.HttpError(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	case stderrors.Is(err, errors.ErrNotFound):
+		errors.HttpError(w, "label "+label+" not found", http.StatusNotFound)
+		return nil, false
+	case err != nil:
+		log.Println(err)
+		errors.HttpError(w, "failed to list applications", http.StatusInternalServerError)
+		return nil, false
+	}
+	return envs, true
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(v)
+}
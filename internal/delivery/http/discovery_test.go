@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/delivery/http/middleware"
+	"github.com/KAnggara75/conflect/internal/repository"
+	"github.com/KAnggara75/conflect/internal/service"
+)
+
+func TestHandleLabels(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, f := range []string{
+		"main/prod/orders-prod.yaml",
+		"main/prod/billing-prod.yaml",
+		"main/staging/orders-staging.yaml",
+		"release/prod/orders-prod.yaml",
+	} {
+		full := filepath.Join(tmpDir, filepath.FromSlash(f))
+		_ = os.MkdirAll(filepath.Dir(full), 0755)
+		_ = os.WriteFile(full, []byte("a: 1"), 0644)
+	}
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := service.NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+	srv := &Server{cfg: cfg, configService: cs}
+
+	scoped := middleware.Scope{"orders/prod/*"}
+	tests := []struct {
+		name  string
+		path  string
+		scope middleware.Scope
+		code  int
+		body  string
+	}{
+		{"Labels", "/api/labels", nil, http.StatusOK, `{"labels":["main","release"]}`},
+		{"Envs", "/api/labels/main/envs", nil, http.StatusOK, `{"label":"main","environments":["prod","staging"]}`},
+		{"Apps", "/api/labels/main/envs/prod/apps", nil, http.StatusOK, `{"label":"main","environment":"prod","applications":["billing","orders"]}`},
+		{"Scoped envs", "/api/labels/main/envs", scoped, http.StatusOK, `{"label":"main","environments":["prod"]}`},
+		{"Scoped apps", "/api/labels/main/envs/prod/apps", scoped, http.StatusOK, `{"label":"main","environment":"prod","applications":["orders"]}`},
+		{"Scoped labels", "/api/labels", middleware.Scope{"*/*/release"}, http.StatusOK, `{"labels":["release"]}`},
+		{"Out of scope env", "/api/labels/main/envs/staging/apps", scoped, http.StatusNotFound, ""},
+		{"Out of scope label", "/api/labels/main/envs", middleware.Scope{"*/*/release"}, http.StatusNotFound, ""},
+		{"Unknown label", "/api/labels/missing/envs", nil, http.StatusNotFound, ""},
+		{"Unknown env", "/api/labels/main/envs/dev/apps", nil, http.StatusNotFound, ""},
+		{"Invalid path", "/api/labels/main", nil, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.scope != nil {
+				req = req.WithContext(middleware.WithScope(req.Context(), tt.scope))
+			}
+			rec := httptest.NewRecorder()
+			srv.handleLabels(rec, req)
+
+			if rec.Code != tt.code {
+				t.Fatalf("expected %d, got %d: %s", tt.code, rec.Code, rec.Body.String())
+			}
+			if tt.body != "" && strings.TrimSpace(rec.Body.String()) != tt.body {
+				t.Errorf("unexpected body %s, want %s", rec.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestAuthorize_ConfigEndpoints(t *testing.T) {
+	tmpDir := t.TempDir()
+	envDir := filepath.Join(tmpDir, "main", "prod")
+	_ = os.MkdirAll(envDir, 0755)
+	_ = os.WriteFile(filepath.Join(envDir, "orders-prod.yaml"), []byte("a: 1"), 0644)
+	_ = os.WriteFile(filepath.Join(envDir, "billing-prod.yaml"), []byte("a: 1"), 0644)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := service.NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+	srv := &Server{cfg: cfg, configService: cs}
+	scope := middleware.Scope{"orders/prod/main"}
+
+	tests := []struct {
+		path    string
+		handler http.HandlerFunc
+		code    int
+	}{
+		{"/orders/prod", srv.handleConfig, http.StatusOK},
+		{"/billing/prod", srv.handleConfig, http.StatusForbidden},
+		{"/orders/prod,dev", srv.handleConfig, http.StatusForbidden},
+		{"/main/orders-prod.yml", srv.handleConfig, http.StatusOK},
+		{"/main/billing-prod.yml", srv.handleConfig, http.StatusForbidden},
+		{"/billing/prod/main/prod/billing-prod.yaml", srv.handleConfig, http.StatusForbidden},
+		{"/orders/prod/main/orders-prod.yaml", srv.handleConfig, http.StatusOK},
+		{"/orders/prod/main/billing-prod.yaml", srv.handleConfig, http.StatusBadRequest},
+		{"/api/effective/billing/prod", srv.handleEffectiveConfig, http.StatusForbidden},
+		{"/api/property/billing/prod?key=a", srv.handleProperty, http.StatusForbidden},
+		{"/api/diff/orders?env=prod&from=main&to=release", srv.handleDiff, http.StatusForbidden},
+		{"/api/history/billing/prod", srv.handleHistory, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req = req.WithContext(middleware.WithScope(req.Context(), scope))
+		rec := httptest.NewRecorder()
+		tt.handler(rec, req)
+		if rec.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d: %s", tt.path, tt.code, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -15,11 +15,14 @@
 
 package dto
 
+import "time"
+
 type ConfigResponse struct {
 	Name            string           `json:"name"`
 	Profiles        []string         `json:"profiles"`
 	Label           string           `json:"label,omitempty"`
 	Version         string           `json:"version,omitempty"`
+	LastCommit      string           `json:"lastCommit,omitempty"`
 	PropertySources []PropertySource `json:"propertySources"`
 	Error           string           `json:"error,omitempty"`
 }
@@ -28,3 +31,137 @@ type PropertySource struct {
 	Name   string         `json:"name"`
 	Source map[string]any `json:"source"`
 }
+
+// EffectiveConfigResponse is the merged view of a config response: one value per key
+// together with where it came from.
+type EffectiveConfigResponse struct {
+	Name       string                       `json:"name"`
+	Profiles   []string                     `json:"profiles"`
+	Label      string                       `json:"label,omitempty"`
+	Version    string                       `json:"version,omitempty"`
+	LastCommit string                       `json:"lastCommit,omitempty"`
+	Properties map[string]EffectiveProperty `json:"properties"`
+	Error      string                       `json:"error,omitempty"`
+}
+
+type EffectiveProperty struct {
+	Value     any               `json:"value"`
+	Source    string            `json:"source"`
+	Commit    string            `json:"commit,omitempty"`
+	Overrides []OverriddenValue `json:"overrides,omitempty"`
+}
+
+// OverriddenValue is a value from a lower-priority source hidden by the winning one.
+type OverriddenValue struct {
+	Value  any    `json:"value"`
+	Source string `json:"source"`
+}
+
+// PropertyResponse holds the effective values of selected keys.
+type PropertyResponse struct {
+	Name       string                   `json:"name"`
+	Profiles   []string                 `json:"profiles"`
+	Label      string                   `json:"label,omitempty"`
+	Version    string                   `json:"version,omitempty"`
+	Properties map[string]PropertyValue `json:"properties"`
+	Error      string                   `json:"error,omitempty"`
+}
+
+type PropertyValue struct {
+	Value  any    `json:"value"`
+	Source string `json:"source"`
+}
+
+// DiffResponse lists the effective keys that differ between two resolved configs.
+type DiffResponse struct {
+	Name    string                 `json:"name"`
+	From    DiffSide               `json:"from"`
+	To      DiffSide               `json:"to"`
+	Added   map[string]any         `json:"added"`
+	Removed map[string]any         `json:"removed"`
+	Changed map[string]ValueChange `json:"changed"`
+}
+
+type DiffSide struct {
+	Profiles []string `json:"profiles"`
+	Label    string   `json:"label"`
+	Version  string   `json:"version,omitempty"`
+}
+
+type ValueChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// HistoryResponse lists the commits that changed the effective config of an
+// application, newest first. Truncated is set when older history was not examined.
+type HistoryResponse struct {
+	Name      string         `json:"name"`
+	Profiles  []string       `json:"profiles"`
+	Label     string         `json:"label"`
+	Commits   []HistoryEntry `json:"commits"`
+	Truncated bool           `json:"truncated,omitempty"`
+}
+
+type HistoryEntry struct {
+	Commit    string                 `json:"commit"`
+	Author    string                 `json:"author"`
+	Email     string                 `json:"email,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+	Message   string                 `json:"message"`
+	Added     map[string]any         `json:"added"`
+	Removed   map[string]any         `json:"removed"`
+	Changed   map[string]ValueChange `json:"changed"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+type LabelsResponse struct {
+	Labels []string `json:"labels"`
+}
+
+type EnvironmentsResponse struct {
+	Label        string   `json:"label"`
+	Environments []string `json:"environments"`
+}
+
+type ApplicationsResponse struct {
+	Label        string   `json:"label"`
+	Environment  string   `json:"environment"`
+	Applications []string `json:"applications"`
+}
+
+// BatchRequest lists the configs to load in one request.
+type BatchRequest struct {
+	Requests []BatchItem `json:"requests"`
+}
+
+type BatchItem struct {
+	App      string   `json:"app"`
+	Profiles []string `json:"profiles"`
+	Label    string   `json:"label,omitempty"`
+}
+
+// BatchResponse holds one result per requested config, in request order.
+type BatchResponse struct {
+	Results []BatchResult `json:"results"`
+}
+
+type BatchResult struct {
+	Status int             `json:"status"`
+	Config *ConfigResponse `json:"config,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// ChangeEvent reports that the resolved config of an application changed, naming the
+// effective keys that were added, removed or changed. Values are left out. Reset
+// marks an event listing every current key because the client's earlier position
+// could not be resolved.
+type ChangeEvent struct {
+	Name        string   `json:"name"`
+	Profiles    []string `json:"profiles"`
+	Label       string   `json:"label"`
+	Version     string   `json:"version,omitempty"`
+	ChangedKeys []string `json:"changedKeys,omitempty"`
+	Error       string   `json:"error,omitempty"`
+	Reset       bool     `json:"reset,omitempty"`
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect http
+ */
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/KAnggara75/conflect/internal/errors"
+)
+
+// handleEffectiveConfig serves GET /api/effective/{app}/{env}/{label?}: the merged
+// config with per-key provenance.
+func (s *Server) handleEffectiveConfig(w http.ResponseWriter, r *http.Request) {
+	appName, env, label, ok := parseConfigPath(r.URL.Path, "/api/effective/")
+	if !ok {
+		http.Error(w, `{"error":"invalid path, expected /api/effective/{app}/{env}/{label?}"}`, http.StatusBadRequest)
+		return
+	}
+	if !s.authorize(w, r, appName, env, label) {
+		return
+	}
+
+	opts, err := loadOptionsFromQuery(r)
+	if err != nil {
+		errors.HttpError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := s.configService.EffectiveConfig(appName, env, label, opts)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if resp.Error != "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_ = json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	if len(resp.Properties) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		resp.Error = "config for " + appName + " with env " + env + " not found"
+		_ = json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
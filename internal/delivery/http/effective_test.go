@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/repository"
+	"github.com/KAnggara75/conflect/internal/service"
+)
+
+func TestHandleEffectiveConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	envDir := filepath.Join(tmpDir, "main", "prod")
+	_ = os.MkdirAll(envDir, 0755)
+	_ = os.WriteFile(filepath.Join(envDir, "myapp-prod.yaml"), []byte("key: prod"), 0644)
+	_ = os.WriteFile(filepath.Join(envDir, "application.yaml"), []byte("key: default\nother: 1"), 0644)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := service.NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+	srv := &Server{cfg: cfg, configService: cs}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/effective/myapp/prod/main", nil)
+	rec := httptest.NewRecorder()
+	srv.handleEffectiveConfig(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp dto.EffectiveConfigResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	key := resp.Properties["key"]
+	if key.Value != "prod" || key.Source != "myapp-prod.yaml" || len(key.Overrides) != 1 {
+		t.Errorf("unexpected provenance for key: %+v", key)
+	}
+
+	t.Run("Not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/effective/unknown/dev/main", nil)
+		rec := httptest.NewRecorder()
+		srv.handleEffectiveConfig(rec, req)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Invalid path", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/effective/myapp", nil)
+		rec := httptest.NewRecorder()
+		srv.handleEffectiveConfig(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rec.Code)
+		}
+	})
+}
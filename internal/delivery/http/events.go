@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect http
+ */
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/errors"
+	"github.com/KAnggara75/conflect/internal/service"
+)
+
+// maxEventSelections caps the configs a single event stream may follow.
+const maxEventSelections = 100
+
+// eventsWriteTimeout bounds each write to an event stream, so a stalled client is
+// dropped instead of holding the stream open.
+const eventsWriteTimeout = 10 * time.Second
+
+// eventsHeartbeat is how long an event stream may stay silent before a comment is
+// sent to keep proxies from closing it.
+var eventsHeartbeat = 15 * time.Second
+
+// handleEvents serves GET /api/events?select={app}/{env}/{label?}, a Server-Sent
+// Events stream with a "ready" event listing the selected configs, then a "change"
+// event each time a branch update changes one of them. The event id is a cursor: a
+// client reconnecting with it in Last-Event-ID first receives what it missed.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		errors.HttpError(w, "method not allowed, use GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	values := r.URL.Query()["select"]
+	if len(values) == 0 || len(values) > maxEventSelections {
+		errors.HttpError(w, fmt.Sprintf("expected 1 to %d select parameters", maxEventSelections), http.StatusBadRequest)
+		return
+	}
+
+	selections := make([]service.Selection, 0, len(values))
+	for _, v := range values {
+		appName, env, label, ok := parseConfigPath("/"+v, "/")
+		if !ok || appName == "" || env == "" {
+			errors.HttpError(w, fmt.Sprintf("invalid select %q, expected {app}/{env}/{label?}", v), http.StatusBadRequest)
+			return
+		}
+		if !s.authorize(w, r, appName, env, label) {
+			return
+		}
+		selections = append(selections, service.Selection{App: appName, Env: env, Label: label})
+	}
+
+	opts, err := loadOptionsFromQuery(r)
+	if err != nil {
+		errors.HttpError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stream, missed := s.configService.NewChangeStream(selections, r.Header.Get("Last-Event-ID"), opts)
+
+	rc := http.NewResponseController(w)
+	send := func(write func(io.Writer) error) bool {
+		// the server's write timeout would otherwise end the stream
+		_ = rc.SetWriteDeadline(time.Now().Add(eventsWriteTimeout))
+		return write(w) == nil && rc.Flush() == nil
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if !send(func(out io.Writer) error {
+		// with nothing missed, the ready event already marks where the client is
+		id := ""
+		if len(missed) == 0 {
+			id = stream.Cursor()
+		}
+		if err := writeEvent(out, "ready", id, stream.Configs()); err != nil {
+			return err
+		}
+		return writeChanges(out, missed, stream.Cursor())
+	}) {
+		return
+	}
+
+	for {
+		ctx, cancel := context.WithTimeout(r.Context(), eventsHeartbeat)
+		changes, ok := stream.Next(ctx)
+		cancel()
+
+		switch {
+		case r.Context().Err() != nil:
+			return
+		case !ok:
+			if !send(func(out io.Writer) error {
+				_, err := io.WriteString(out, ": heartbeat\n\n")
+				return err
+			}) {
+				return
+			}
+		case len(changes) > 0:
+			if !send(func(out io.Writer) error { return writeChanges(out, changes, stream.Cursor()) }) {
+				return
+			}
+		}
+	}
+}
+
+// writeChanges writes one "change" event per config. Only the last carries the
+// cursor, so a client cut off halfway resumes before the whole batch.
+func writeChanges(w io.Writer, changes []dto.ChangeEvent, cursor string) error {
+	for i, change := range changes {
+		id := ""
+		if i == len(changes)-1 {
+			id = cursor
+		}
+		if err := writeEvent(w, "change", id, change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeEvent writes data as a JSON Server-Sent Event. An empty id leaves the client's
+// last event id as it was.
+func writeEvent(w io.Writer, event, id string, data any) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	if id != "" {
+		b.WriteString("id: " + id + "\n")
+	}
+	b.WriteString("event: " + event + "\n")
+	b.WriteString("data: " + string(body) + "\n\n")
+	_, err = io.WriteString(w, b.String())
+	return err
+}
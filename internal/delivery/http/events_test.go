@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/delivery/http/middleware"
+	"github.com/KAnggara75/conflect/internal/repository"
+	"github.com/KAnggara75/conflect/internal/service"
+)
+
+func TestHandleEvents(t *testing.T) {
+	tmpDir := t.TempDir()
+	envDir := filepath.Join(tmpDir, "main", "prod")
+	_ = os.MkdirAll(envDir, 0755)
+	_ = os.WriteFile(filepath.Join(envDir, "myapp-prod.yaml"), []byte("key: value"), 0644)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := service.NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+	srv := &Server{cfg: cfg, configService: cs}
+
+	heartbeat := eventsHeartbeat
+	eventsHeartbeat = 20 * time.Millisecond
+	t.Cleanup(func() { eventsHeartbeat = heartbeat })
+
+	t.Run("Stream", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		req := httptest.NewRequest(http.MethodGet, "/api/events?select=myapp/prod/main", nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		srv.handleEvents(rec, req)
+
+		body := rec.Body.String()
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/event-stream" {
+			t.Fatalf("expected an event stream, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+		}
+		if !strings.HasPrefix(body, "event: ready\ndata: [{\"name\":\"myapp\",\"profiles\":[\"prod\"],\"label\":\"main\"") {
+			t.Errorf("expected a ready event first, got %q", body)
+		}
+		if !strings.Contains(body, "\n\n: heartbeat\n\n") {
+			t.Errorf("expected heartbeat comments, got %q", body)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		path  string
+		scope middleware.Scope
+		code  int
+	}{
+		{"No selection", "/api/events", nil, http.StatusBadRequest},
+		{"Invalid selection", "/api/events?select=myapp", nil, http.StatusBadRequest},
+		{"Out of scope", "/api/events?select=myapp/prod&select=other/prod", middleware.Scope{"myapp/*/*"}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.scope != nil {
+				req = req.WithContext(middleware.WithScope(req.Context(), tt.scope))
+			}
+			rec := httptest.NewRecorder()
+			srv.handleEvents(rec, req)
+			if rec.Code != tt.code {
+				t.Errorf("expected %d, got %d: %s", tt.code, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect http
+ */
+
+package http
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/errors"
+	"github.com/KAnggara75/conflect/internal/helper"
+	"github.com/KAnggara75/conflect/internal/service"
+)
+
+// formatExtensions maps the file extensions of /{label}/{app}-{profile}.{ext} to
+// output formats.
+var formatExtensions = map[string]string{
+	".yml":        helper.FormatYAML,
+	".yaml":       helper.FormatYAML,
+	".json":       helper.FormatJSON,
+	".properties": helper.FormatProperties,
+	".env":        helper.FormatDotenv,
+}
+
+// formatMediaTypes maps Accept media types to output formats. application/json is
+// the native property-source response and maps to "".
+var formatMediaTypes = map[string]string{
+	"application/json":       "",
+	"application/yaml":       helper.FormatYAML,
+	"application/x-yaml":     helper.FormatYAML,
+	"text/yaml":              helper.FormatYAML,
+	"text/x-yaml":            helper.FormatYAML,
+	"text/x-java-properties": helper.FormatProperties,
+	"text/plain":             helper.FormatProperties,
+}
+
+var formatContentTypes = map[string]string{
+	helper.FormatYAML:       "text/yaml; charset=utf-8",
+	helper.FormatJSON:       "application/json",
+	helper.FormatProperties: "text/plain; charset=utf-8",
+	helper.FormatDotenv:     "text/plain; charset=utf-8",
+}
+
+// parseFormattedPath recognises /{app}-{profile}.{ext} and /{label}/{app}-{profile}.{ext}.
+// The name is split at its last dash, so profiles cannot contain dashes here.
+func parseFormattedPath(urlPath string) (appName, env, label, format string, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(urlPath, "/"), "/")
+	if len(parts) > 2 {
+		return "", "", "", "", false
+	}
+
+	name := parts[len(parts)-1]
+	format, ok = formatExtensions[path.Ext(name)]
+	if !ok {
+		return "", "", "", "", false
+	}
+
+	base := strings.TrimSuffix(name, path.Ext(name))
+	i := strings.LastIndexByte(base, '-')
+	if i <= 0 || i == len(base)-1 {
+		return "", "", "", "", false
+	}
+
+	if len(parts) == 2 {
+		label = parts[0]
+	}
+	return base[:i], base[i+1:], label, format, true
+}
+
+// negotiateFormat picks an output format from an Accept header, honouring q-values.
+// It returns "" for the native JSON response, which also wins ties and wildcards.
+func negotiateFormat(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		format, known := formatMediaTypes[mediaType]
+		if !known {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		if q > bestQ || (q == bestQ && format == "") {
+			best, bestQ = format, q
+		}
+	}
+	return best
+}
+
+// writeFormatted renders the merged config in format.
+func writeFormatted(w http.ResponseWriter, resp *dto.ConfigResponse, format string) {
+	body, err := helper.Render(service.MergeSources(resp.PropertySources), format)
+	if err != nil {
+		errors.HttpError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.Header().Set("Content-Type", formatContentTypes[format])
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/helper"
+	"github.com/KAnggara75/conflect/internal/repository"
+	"github.com/KAnggara75/conflect/internal/service"
+)
+
+func TestParseFormattedPath(t *testing.T) {
+	tests := []struct {
+		path   string
+		app    string
+		env    string
+		label  string
+		format string
+		ok     bool
+	}{
+		{"/main/myapp-prod.yml", "myapp", "prod", "main", helper.FormatYAML, true},
+		{"/myapp-prod.properties", "myapp", "prod", "", helper.FormatProperties, true},
+		{"/main/my-app-prod,mysql.json", "my-app", "prod,mysql", "main", helper.FormatJSON, true},
+		{"/main/myapp-prod.env", "myapp", "prod", "main", helper.FormatDotenv, true},
+		{"/myapp/prod", "", "", "", "", false},
+		{"/myapp/prod/main", "", "", "", "", false},
+		{"/main/myapp.yml", "", "", "", "", false},
+		{"/main/myapp-.yml", "", "", "", "", false},
+		{"/a/b/myapp-prod.yml", "", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		app, env, label, format, ok := parseFormattedPath(tt.path)
+		if ok != tt.ok || app != tt.app || env != tt.env || label != tt.label || format != tt.format {
+			t.Errorf("parseFormattedPath(%q) = %q, %q, %q, %q, %v", tt.path, app, env, label, format, ok)
+		}
+	}
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"*/*", ""},
+		{"application/json", ""},
+		{"application/x-yaml", helper.FormatYAML},
+		{"text/yaml, application/json", ""},
+		{"application/json;q=0.5, text/yaml", helper.FormatYAML},
+		{"text/plain", helper.FormatProperties},
+		{"text/html, */*;q=0.8", ""},
+		{"text/yaml;q=0", ""},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateFormat(tt.header); got != tt.want {
+			t.Errorf("negotiateFormat(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestHandleConfig_Formats(t *testing.T) {
+	tmpDir := t.TempDir()
+	envDir := filepath.Join(tmpDir, "main", "prod")
+	_ = os.MkdirAll(envDir, 0755)
+	_ = os.WriteFile(filepath.Join(envDir, "myapp-prod.yaml"), []byte("server:\n  port: 80\n"), 0644)
+	_ = os.WriteFile(filepath.Join(envDir, "application.yaml"), []byte("server:\n  port: 8080\n  host: localhost\n"), 0644)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := service.NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+	srv := &Server{cfg: cfg, configService: cs}
+
+	tests := []struct {
+		name        string
+		path        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"YAML file", "/main/myapp-prod.yml", "", "text/yaml; charset=utf-8", "server:\n    host: localhost\n    port: 80\n"},
+		{"Properties file", "/main/myapp-prod.properties", "", "text/plain; charset=utf-8", "server.host=localhost\nserver.port=80\n"},
+		{"Dotenv file", "/main/myapp-prod.env", "", "text/plain; charset=utf-8", "SERVER_HOST=\"localhost\"\nSERVER_PORT=\"80\"\n"},
+		{"JSON file", "/main/myapp-prod.json", "", "application/json", "{\n  \"server\": {\n    \"host\": \"localhost\",\n    \"port\": 80\n  }\n}\n"},
+		{"Accept YAML", "/myapp/prod/main", "application/x-yaml", "text/yaml; charset=utf-8", "server:\n    host: localhost\n    port: 80\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			srv.handleConfig(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Errorf("expected Content-Type %q, got %q", tt.contentType, ct)
+			}
+			if rec.Body.String() != tt.body {
+				t.Errorf("unexpected body:\n%s\nwant\n%s", rec.Body.String(), tt.body)
+			}
+		})
+	}
+
+	t.Run("Missing config", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/main/unknown-dev.yml", nil)
+		rec := httptest.NewRecorder()
+		srv.handleConfig(rec, req)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rec.Code)
+		}
+	})
+}
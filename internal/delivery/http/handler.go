@@ -17,6 +17,8 @@ package http
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -27,6 +29,7 @@ import (
 
 	"github.com/KAnggara75/conflect/internal/config"
 	"github.com/KAnggara75/conflect/internal/delivery/http/middleware"
+	"github.com/KAnggara75/conflect/internal/errors"
 	"github.com/KAnggara75/conflect/internal/service"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -72,7 +75,7 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/health", s.health)
 
 	// Wrap middleware
-	authCfg := middleware.AuthConfig{Token: s.cfg.Token}
+	authCfg := middleware.AuthConfig{Token: s.cfg.Token, Scopes: s.cfg.ScopedTokens}
 
 	// Route yang butuh middleware
 	webhookMux := http.NewServeMux()
@@ -88,6 +91,15 @@ func (s *Server) Start() error {
 
 	protectedMux := http.NewServeMux()
 	protectedMux.HandleFunc("/", s.handleConfig)
+	protectedMux.HandleFunc("/api/effective/", s.handleEffectiveConfig)
+	protectedMux.HandleFunc("/api/property/", s.handleProperty)
+	protectedMux.HandleFunc("/api/diff/", s.handleDiff)
+	protectedMux.HandleFunc("/api/history/", s.handleHistory)
+	protectedMux.HandleFunc("/api/batch", s.handleBatch)
+	protectedMux.HandleFunc("/api/watch/", s.handleWatch)
+	protectedMux.HandleFunc("/api/events", s.handleEvents)
+	protectedMux.HandleFunc("/api/labels", s.handleLabels)
+	protectedMux.HandleFunc("/api/labels/", s.handleLabels)
 
 	// Chain untuk endpoint yang dilindungi
 	protectedHandler := middleware.Chain(
@@ -95,6 +107,7 @@ func (s *Server) Start() error {
 		middleware.Logging,
 		middleware.RateLimitMiddleware(s.cfg.Limit, time.Minute),
 		middleware.AuthMiddleware(authCfg),
+		middleware.Compress(s.cfg.CompressMin),
 	)
 
 	// Gabungkan kedua mux
@@ -215,29 +228,157 @@ func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
-	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
-	if len(parts) < 2 {
-		http.Error(w, `{"error":"invalid path, expected /{app}/{env}/{label?}"}`, http.StatusBadRequest)
+	// /{app}/{env}/{label}/{path...} serves a raw file; /{app}/{env}/{label}/ is still the config
+	if parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 4); len(parts) == 4 && parts[3] != "" {
+		if !s.authorize(w, r, parts[0], parts[1], parts[2]) {
+			return
+		}
+		s.handleResource(w, r, parts[0], parts[1], parts[2], parts[3])
 		return
 	}
 
-	appName := parts[0]
-	env := parts[1]
-	label := ""
-	if len(parts) > 2 {
-		label = parts[2]
+	appName, env, label, format, ok := parseFormattedPath(r.URL.Path)
+	if !ok {
+		appName, env, label, ok = parseConfigPath(r.URL.Path, "/")
+		if !ok {
+			http.Error(w, `{"error":"invalid path, expected /{app}/{env}/{label?}"}`, http.StatusBadRequest)
+			return
+		}
+		format = negotiateFormat(r.Header.Get("Accept"))
+	}
+	if !s.authorize(w, r, appName, env, label) {
+		return
 	}
 
-	resp := s.configService.LoadConfig(appName, env, label)
+	opts, err := loadOptionsFromQuery(r)
+	if err != nil {
+		errors.HttpError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rev, err := revisionFromQuery(r)
+	if err != nil {
+		errors.HttpError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.configService.LoadConfigAt(appName, env, label, rev, opts)
+	if stderrors.Is(err, errors.ErrNotFound) {
+		errors.HttpError(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Println(err)
+		errors.HttpError(w, "failed to load config", http.StatusInternalServerError)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 
+	if resp.Error != "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_ = json.NewEncoder(w).Encode(resp)
+		return
+	}
+
 	// kalau tidak ada property sources, return 404
 	if len(resp.PropertySources) == 0 {
 		w.WriteHeader(http.StatusNotFound)
 		resp.Error = "config for " + appName + " with env " + env + " not found"
-	} else {
-		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept")
+	etag := configETag(resp, format+"?"+r.URL.Query().Encode())
+	s.setCacheHeaders(w, etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
 	}
+
+	if format != "" {
+		writeFormatted(w, resp, format)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(resp)
 }
+
+// parseConfigPath splits {prefix}{app}/{env}/{label?} into its segments.
+func parseConfigPath(urlPath, prefix string) (appName, env, label string, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(urlPath, prefix), "/")
+	if len(parts) < 2 {
+		return "", "", "", false
+	}
+	if len(parts) > 2 {
+		label = parts[2]
+	}
+	return parts[0], parts[1], label, true
+}
+
+// authorize reports whether the caller's scope covers appName with every profile of
+// env on label, and writes 403 Forbidden if it does not.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, appName, env, label string) bool {
+	scope := middleware.ScopeFromContext(r.Context())
+	if scope == nil {
+		return true
+	}
+
+	if label == "" {
+		label = s.cfg.DefaultBranch
+	}
+	if !scopeAllows(scope, appName, strings.Split(env, ","), label) {
+		errors.HttpError(w, "access denied for "+appName+" with env "+env+" on "+label, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// scopeAllows reports whether scope covers appName with every one of profiles on label.
+func scopeAllows(scope middleware.Scope, appName string, profiles []string, label string) bool {
+	for _, profile := range profiles {
+		profile = strings.TrimSpace(profile)
+		// empty segments would match any pattern
+		if appName == "" || profile == "" || !scope.Allows(appName, profile, label) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadOptionsFromQuery reads per-request overrides of the server config settings.
+func loadOptionsFromQuery(r *http.Request) (service.LoadOptions, error) {
+	var opts service.LoadOptions
+
+	switch flatten := strings.ToLower(r.URL.Query().Get("flatten")); flatten {
+	case "", config.ArrayFlattenRaw, config.ArrayFlattenIndexed:
+		opts.ArrayFlatten = flatten
+	default:
+		return opts, fmt.Errorf("invalid flatten %q, expected %q or %q", flatten, config.ArrayFlattenRaw, config.ArrayFlattenIndexed)
+	}
+
+	return opts, nil
+}
+
+// revisionFromQuery reads the commit= or at=<RFC3339 timestamp> parameter that pins
+// a request to the label's history.
+func revisionFromQuery(r *http.Request) (service.Revision, error) {
+	var rev service.Revision
+	query := r.URL.Query()
+
+	rev.Commit = query.Get("commit")
+	if at := query.Get("at"); at != "" {
+		if rev.Commit != "" {
+			return rev, fmt.Errorf("commit and at cannot be combined")
+		}
+		t, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			return rev, fmt.Errorf("invalid at %q, expected an RFC3339 timestamp", at)
+		}
+		rev.At = t
+	}
+
+	return rev, nil
+}
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
 	"github.com/KAnggara75/conflect/internal/repository"
 	"github.com/KAnggara75/conflect/internal/service"
 )
@@ -149,6 +150,7 @@ func TestHandleConfig(t *testing.T) {
 	envDir := filepath.Join(tmpDir, "main", "production")
 	_ = os.MkdirAll(envDir, 0755)
 	_ = os.WriteFile(filepath.Join(envDir, "myapp-production.yaml"), []byte("key: value"), 0644)
+	_ = os.WriteFile(filepath.Join(envDir, "listapp-production.yaml"), []byte("servers:\n  - host: a\n"), 0644)
 
 	cfg := &config.Config{
 		RepoPath:      tmpDir,
@@ -187,6 +189,52 @@ func TestHandleConfig(t *testing.T) {
 			t.Errorf("expected 200, got %d", rec.Code)
 		}
 	})
+
+	t.Run("Indexed flatten query", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/listapp/production/main?flatten=indexed", nil)
+		rec := httptest.NewRecorder()
+		srv.handleConfig(rec, req)
+
+		var resp dto.ConfigResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to parse response body: %v", err)
+		}
+		if len(resp.PropertySources) != 1 || resp.PropertySources[0].Source["servers[0].host"] != "a" {
+			t.Errorf("expected indexed key servers[0].host, got %v", resp.PropertySources)
+		}
+	})
+
+	t.Run("Invalid flatten query", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/myapp/production/main?flatten=nested", nil)
+		rec := httptest.NewRecorder()
+		srv.handleConfig(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Invalid point in time", func(t *testing.T) {
+		for _, query := range []string{"at=yesterday", "at=2025-09-01T14:05:00Z&commit=abc1234"} {
+			req := httptest.NewRequest("GET", "/myapp/production/main?"+query, nil)
+			rec := httptest.NewRecorder()
+			srv.handleConfig(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", query, rec.Code)
+			}
+		}
+	})
+
+	t.Run("Unknown commit", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/myapp/production/main?commit=deadbeef", nil)
+		rec := httptest.NewRecorder()
+		srv.handleConfig(rec, req)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rec.Code)
+		}
+	})
 }
 
 func TestHealth(t *testing.T) {
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect http
+ */
+
+package http
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/KAnggara75/conflect/internal/errors"
+	"github.com/KAnggara75/conflect/internal/service"
+)
+
+// maxHistoryLimit caps the limit parameter of the history endpoint.
+const maxHistoryLimit = 100
+
+// handleHistory serves GET /api/history/{app}/{env}/{label?}: the commits that
+// changed the effective config, newest first.
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	appName, env, label, ok := parseConfigPath(r.URL.Path, "/api/history/")
+	if !ok {
+		http.Error(w, `{"error":"invalid path, expected /api/history/{app}/{env}/{label?}"}`, http.StatusBadRequest)
+		return
+	}
+	if !s.authorize(w, r, appName, env, label) {
+		return
+	}
+
+	limit := service.DefaultHistoryLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxHistoryLimit {
+			errors.HttpError(w, fmt.Sprintf("invalid limit %q, expected 1 to %d", v, maxHistoryLimit), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	opts, err := loadOptionsFromQuery(r)
+	if err != nil {
+		errors.HttpError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	history, err := s.configService.ConfigHistory(appName, env, label, limit, opts)
+	switch {
+	case stderrors.Is(err, errors.ErrInvalidInput):
+		errors.HttpError(w, err.Error(), http.StatusBadRequest)
+		return
+	case stderrors.Is(err, errors.ErrNotFound):
+		errors.HttpError(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		log.Println(err)
+		errors.HttpError(w, "failed to read config history", http.StatusInternalServerError)
+		return
+	}
+
+	if len(history.Commits) == 0 {
+		errors.HttpError(w, "no history for "+appName+" with env "+env, http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(history)
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/repository"
+	"github.com/KAnggara75/conflect/internal/service"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestHandleHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	mainDir := filepath.Join(tmpDir, "main")
+	_ = os.MkdirAll(filepath.Join(mainDir, "prod"), 0755)
+
+	gitRepo, err := git.PlainInit(mainDir, false)
+	if err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+	wt, _ := gitRepo.Worktree()
+	for i, content := range []string{"token: a\n", "token: b\nport: 80\n"} {
+		_ = os.WriteFile(filepath.Join(mainDir, "prod", "myapp-prod.yaml"), []byte(content), 0644)
+		_, _ = wt.Add("prod/myapp-prod.yaml")
+		_, err := wt.Commit("change", &git.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now().Add(time.Duration(i-2) * time.Minute)},
+		})
+		if err != nil {
+			t.Fatalf("failed to commit: %v", err)
+		}
+	}
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := service.NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+	srv := &Server{cfg: cfg, configService: cs}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/history/myapp/prod/main", nil)
+	rec := httptest.NewRecorder()
+	srv.handleHistory(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp dto.HistoryResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Commits) != 2 {
+		t.Fatalf("expected 2 commits, got %+v", resp.Commits)
+	}
+	latest := resp.Commits[0]
+	if latest.Changed["token"].To != service.MaskedValue || latest.Added["port"] != float64(80) {
+		t.Errorf("unexpected latest entry %+v", latest)
+	}
+
+	for name, tt := range map[string]struct {
+		path string
+		code int
+	}{
+		"Invalid path":  {"/api/history/myapp", http.StatusBadRequest},
+		"Invalid limit": {"/api/history/myapp/prod/main?limit=0", http.StatusBadRequest},
+		"Unknown app":   {"/api/history/unknown/prod/main", http.StatusNotFound},
+		"Unknown label": {"/api/history/myapp/prod/missing", http.StatusNotFound},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+			srv.handleHistory(rec, req)
+			if rec.Code != tt.code {
+				t.Errorf("expected %d, got %d: %s", tt.code, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -16,13 +16,63 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"path"
 	"strings"
 )
 
 type AuthConfig struct {
 	Token string
+	// Scopes maps additional tokens to the {application}/{profile}/{label} patterns
+	// they may read. Token itself is unrestricted.
+	Scopes map[string][]string
+}
+
+// Scope is the set of {application}/{profile}/{label} glob patterns a caller may read.
+// Missing segments match anything. A nil Scope is unrestricted.
+type Scope []string
+
+type scopeKey struct{}
+
+// ScopeFromContext returns the scope of the authenticated caller, nil when unrestricted.
+func ScopeFromContext(ctx context.Context) Scope {
+	scope, _ := ctx.Value(scopeKey{}).(Scope)
+	return scope
+}
+
+// WithScope returns a copy of ctx carrying scope.
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// Allows reports whether the scope covers app, profile and label. Empty arguments
+// only need some pattern with a matching prefix, which lets discovery list the labels
+// and profiles a caller can see anything in.
+func (s Scope) Allows(app, profile, label string) bool {
+	if s == nil {
+		return true
+	}
+	for _, pattern := range s {
+		if scopeMatches(pattern, app, profile, label) {
+			return true
+		}
+	}
+	return false
+}
+
+func scopeMatches(pattern, app, profile, label string) bool {
+	segments := strings.SplitN(pattern, "/", 3)
+	for i, value := range []string{app, profile, label} {
+		if value == "" || i >= len(segments) {
+			continue
+		}
+		if ok, err := path.Match(segments[i], value); err != nil || !ok {
+			return false
+		}
+	}
+	return true
 }
 
 func AuthMiddleware(cfg AuthConfig) Middleware {
@@ -42,6 +92,10 @@ func AuthMiddleware(cfg AuthConfig) Middleware {
 			}
 
 			token := strings.TrimPrefix(auth, "Bearer ")
+			if patterns, ok := cfg.Scopes[token]; ok && token != cfg.Token {
+				next.ServeHTTP(w, r.WithContext(WithScope(r.Context(), patterns)))
+				return
+			}
 			if token != cfg.Token {
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusUnauthorized)
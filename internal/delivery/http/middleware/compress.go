@@ -0,0 +1,305 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect middleware
+ * https://github.com/PakaiWA/PakaiWA/tree/main/internal/delivery/http/middleware
+ */
+
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	encodingGzip = "gzip"
+	encodingZstd = "zstd"
+)
+
+var (
+	gzipPool = sync.Pool{New: func() any {
+		return gzip.NewWriter(io.Discard)
+	}}
+	zstdPool = sync.Pool{New: func() any {
+		enc, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderConcurrency(1))
+		return enc
+	}}
+)
+
+// Compress negotiates gzip or zstd response compression via Accept-Encoding.
+// Responses smaller than minSize bytes are sent uncompressed; a negative minSize
+// disables compression. /metrics is never compressed, Prometheus negotiates that itself.
+// Compressed bodies get the content-coding appended to their ETag, and If-None-Match
+// is passed on to handlers without it.
+func Compress(minSize int) Middleware {
+	return func(next http.Handler) http.Handler {
+		if minSize < 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/metrics") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				minSize:        minSize,
+				status:         http.StatusOK,
+				ifNoneMatch:    r.Header.Get("If-None-Match"),
+			}
+			defer cw.Close()
+
+			// handlers compare If-None-Match against the ETag of the identity body
+			if cw.ifNoneMatch != "" {
+				r = r.Clone(r.Context())
+				r.Header.Set("If-None-Match", stripETagCodings(cw.ifNoneMatch))
+			}
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding picks zstd or gzip from an Accept-Encoding header, honouring
+// q-values. zstd wins ties. It returns "" when neither is acceptable.
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	qualities := map[string]float64{}
+	wildcard := -1.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if v, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		if name == "*" {
+			wildcard = q
+			continue
+		}
+		qualities[name] = q
+	}
+
+	quality := func(name string) float64 {
+		if q, ok := qualities[name]; ok {
+			return q
+		}
+		if wildcard >= 0 {
+			return wildcard
+		}
+		return 0
+	}
+
+	zq, gq := quality(encodingZstd), quality(encodingGzip)
+	switch {
+	case zq > 0 && zq >= gq:
+		return encodingZstd
+	case gq > 0:
+		return encodingGzip
+	default:
+		return ""
+	}
+}
+
+// compressWriter buffers the response until minSize bytes are written, then decides
+// whether to compress. Handlers that flush early (streams) are sent uncompressed.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+	status   int
+	buf      []byte
+
+	// ifNoneMatch is the request header as sent, with coded ETags
+	ifNoneMatch string
+
+	headerSent  bool
+	passthrough bool
+	encoder     io.WriteCloser
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.headerSent || cw.passthrough {
+		return
+	}
+	cw.status = code
+
+	// a 304 confirms the representation the client holds, so it keeps its ETag
+	if code == http.StatusNotModified {
+		cw.setETagCoding(matchedETagCoding(cw.ifNoneMatch, cw.Header().Get("ETag")))
+	}
+
+	// responses without a body go out untouched
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified {
+		cw.startPassthrough()
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.passthrough {
+		return cw.ResponseWriter.Write(p)
+	}
+	if cw.encoder != nil {
+		return cw.encoder.Write(p)
+	}
+
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= cw.minSize {
+		if err := cw.startEncoding(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush sends whatever is buffered and flushes the underlying writer.
+func (cw *compressWriter) Flush() {
+	if cw.encoder == nil && !cw.passthrough {
+		cw.startPassthrough()
+	}
+	if f, ok := cw.encoder.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Close finishes the compressed stream, or sends a small buffered body as-is.
+func (cw *compressWriter) Close() {
+	if cw.encoder != nil {
+		_ = cw.encoder.Close()
+		switch enc := cw.encoder.(type) {
+		case *gzip.Writer:
+			gzipPool.Put(enc)
+		case *zstd.Encoder:
+			zstdPool.Put(enc)
+		}
+		cw.encoder = nil
+		return
+	}
+	if !cw.passthrough {
+		cw.startPassthrough()
+	}
+}
+
+func (cw *compressWriter) startPassthrough() {
+	cw.passthrough = true
+	if !cw.headerSent {
+		cw.headerSent = true
+		cw.ResponseWriter.WriteHeader(cw.status)
+	}
+	if len(cw.buf) > 0 {
+		_, _ = cw.ResponseWriter.Write(cw.buf)
+		cw.buf = nil
+	}
+}
+
+func (cw *compressWriter) startEncoding() error {
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" || strings.HasPrefix(h.Get("Content-Type"), "text/event-stream") {
+		cw.startPassthrough()
+		return nil
+	}
+
+	h.Set("Content-Encoding", cw.encoding)
+	cw.setETagCoding(cw.encoding)
+	h.Del("Content-Length")
+	cw.headerSent = true
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	switch cw.encoding {
+	case encodingZstd:
+		enc := zstdPool.Get().(*zstd.Encoder)
+		enc.Reset(cw.ResponseWriter)
+		cw.encoder = enc
+	default:
+		enc := gzipPool.Get().(*gzip.Writer)
+		enc.Reset(cw.ResponseWriter)
+		cw.encoder = enc
+	}
+
+	_, err := cw.encoder.Write(cw.buf)
+	cw.buf = nil
+	return err
+}
+
+// setETagCoding marks the ETag of the response as belonging to the body encoded with
+// encoding, so that identity, gzip and zstd bodies never share a strong ETag.
+func (cw *compressWriter) setETagCoding(encoding string) {
+	if etag := cw.Header().Get("ETag"); encoding != "" && strings.HasSuffix(etag, `"`) {
+		cw.Header().Set("ETag", strings.TrimSuffix(etag, `"`)+"-"+encoding+`"`)
+	}
+}
+
+// splitETagCoding splits the content-coding suffix added by setETagCoding off an
+// entity tag.
+func splitETagCoding(tag string) (string, string) {
+	for _, encoding := range []string{encodingGzip, encodingZstd} {
+		if base, ok := strings.CutSuffix(tag, "-"+encoding+`"`); ok {
+			return base + `"`, encoding
+		}
+	}
+	return tag, ""
+}
+
+// stripETagCodings removes the content-coding suffixes from an If-None-Match value.
+func stripETagCodings(ifNoneMatch string) string {
+	tags := strings.Split(ifNoneMatch, ",")
+	for i, tag := range tags {
+		tags[i], _ = splitETagCoding(strings.TrimSpace(tag))
+	}
+	return strings.Join(tags, ", ")
+}
+
+// matchedETagCoding returns the content-coding of the If-None-Match tag that matched
+// etag, or "" when the client holds the identity body.
+func matchedETagCoding(ifNoneMatch, etag string) string {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		base, encoding := splitETagCoding(strings.TrimSpace(tag))
+		if encoding != "" && strings.TrimPrefix(base, "W/") == strings.TrimPrefix(etag, "W/") {
+			return encoding
+		}
+	}
+	return ""
+}
@@ -16,13 +16,18 @@ package middleware
 
 import (
 	"bytes"
+	"compress/gzip"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/klauspost/compress/zstd"
 )
 
 func TestAuthMiddleware(t *testing.T) {
@@ -179,3 +184,228 @@ func TestChainAndLogging(t *testing.T) {
 		t.Errorf("expected 200, got %d", rec.Code)
 	}
 }
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"zstd", "zstd"},
+		{"gzip, zstd", "zstd"},
+		{"gzip;q=1.0, zstd;q=0.5", "gzip"},
+		{"zstd;q=0, gzip", "gzip"},
+		{"br", ""},
+		{"*", "zstd"},
+		{"*;q=0.5, gzip", "gzip"},
+		{"identity", ""},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat(`{"key":"value"},`, 200)
+	handler := Compress(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/small":
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		case "/not-modified":
+			w.WriteHeader(http.StatusNotModified)
+		case "/conditional":
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			_, _ = w.Write([]byte(large))
+		default:
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(large))
+		}
+	}))
+
+	t.Run("Gzip large body", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/myapp/prod", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Header().Get("Content-Encoding") != "gzip" {
+			t.Fatalf("expected gzip encoding, got %q", rec.Header().Get("Content-Encoding"))
+		}
+		gr, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatalf("invalid gzip body: %v", err)
+		}
+		body, _ := io.ReadAll(gr)
+		if string(body) != large {
+			t.Error("decompressed body does not match original")
+		}
+	})
+
+	t.Run("Zstd large body", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/myapp/prod", nil)
+		req.Header.Set("Accept-Encoding", "gzip, zstd")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Header().Get("Content-Encoding") != "zstd" {
+			t.Fatalf("expected zstd encoding, got %q", rec.Header().Get("Content-Encoding"))
+		}
+		dec, _ := zstd.NewReader(rec.Body)
+		defer dec.Close()
+		body, _ := io.ReadAll(dec)
+		if string(body) != large {
+			t.Error("decompressed body does not match original")
+		}
+	})
+
+	t.Run("Below threshold", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/small", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Header().Get("Content-Encoding") != "" {
+			t.Errorf("expected no encoding for small body, got %q", rec.Header().Get("Content-Encoding"))
+		}
+		if rec.Body.String() != `{"ok":true}` {
+			t.Errorf("unexpected body %q", rec.Body.String())
+		}
+		if rec.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("expected Vary: Accept-Encoding, got %q", rec.Header().Get("Vary"))
+		}
+	})
+
+	t.Run("Not modified", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/not-modified", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNotModified || rec.Header().Get("Content-Encoding") != "" {
+			t.Errorf("expected plain 304, got %d with encoding %q", rec.Code, rec.Header().Get("Content-Encoding"))
+		}
+	})
+
+	t.Run("ETag per coding", func(t *testing.T) {
+		for _, encoding := range []string{"", "gzip", "zstd"} {
+			req := httptest.NewRequest("GET", "/conditional", nil)
+			req.Header.Set("Accept-Encoding", encoding)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			want := `"v1"`
+			if encoding != "" {
+				want = `"v1-` + encoding + `"`
+			}
+			if got := rec.Header().Get("ETag"); got != want {
+				t.Errorf("%q: expected ETag %s, got %s", encoding, want, got)
+			}
+
+			req = httptest.NewRequest("GET", "/conditional", nil)
+			req.Header.Set("Accept-Encoding", encoding)
+			req.Header.Set("If-None-Match", want)
+			rec = httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusNotModified || rec.Header().Get("ETag") != want {
+				t.Errorf("%q: expected 304 with ETag %s, got %d with %s", encoding, want, rec.Code, rec.Header().Get("ETag"))
+			}
+		}
+	})
+
+	t.Run("No Accept-Encoding", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/myapp/prod", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != large {
+			t.Error("expected uncompressed body without Accept-Encoding")
+		}
+	})
+
+	t.Run("Metrics excluded", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Header().Get("Content-Encoding") != "" {
+			t.Errorf("expected /metrics to be left alone, got %q", rec.Header().Get("Content-Encoding"))
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		disabled := Compress(-1)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(large))
+		}))
+		req := httptest.NewRequest("GET", "/myapp/prod", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		disabled.ServeHTTP(rec, req)
+
+		if rec.Header().Get("Content-Encoding") != "" {
+			t.Errorf("expected no encoding when disabled, got %q", rec.Header().Get("Content-Encoding"))
+		}
+	})
+}
+
+func TestAuthMiddleware_Scopes(t *testing.T) {
+	cfg := AuthConfig{Token: "secret-token", Scopes: map[string][]string{"ci-token": {"orders/*/main"}}}
+	var got Scope
+	handler := AuthMiddleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = ScopeFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for token, want := range map[string]Scope{"secret-token": nil, "ci-token": {"orders/*/main"}} {
+		got = Scope{"unset"}
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: expected 200, got %d", token, rec.Code)
+		}
+		if (got == nil) != (want == nil) || len(got) != len(want) {
+			t.Errorf("%s: expected scope %v, got %v", token, want, got)
+		}
+	}
+}
+
+func TestScope_Allows(t *testing.T) {
+	scope := Scope{"orders/*/main", "billing/prod", "*/staging/release-*"}
+	tests := []struct {
+		app, profile, label string
+		want                bool
+	}{
+		{"orders", "prod", "main", true},
+		{"orders", "prod", "develop", false},
+		{"billing", "prod", "develop", true},
+		{"billing", "dev", "main", false},
+		{"payments", "staging", "release-1.2", true},
+		{"payments", "prod", "release-1.2", false},
+		{"", "", "main", true},
+		{"", "", "develop", true},
+		{"", "staging", "main", true},
+		{"", "dev", "main", true},
+		{"", "dev", "develop", false},
+	}
+	for _, tt := range tests {
+		if got := scope.Allows(tt.app, tt.profile, tt.label); got != tt.want {
+			t.Errorf("Allows(%q, %q, %q) = %v, want %v", tt.app, tt.profile, tt.label, got, tt.want)
+		}
+	}
+
+	if !Scope(nil).Allows("any", "thing", "goes") {
+		t.Error("expected nil scope to be unrestricted")
+	}
+}
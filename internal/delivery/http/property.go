@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect http
+ */
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/KAnggara75/conflect/internal/errors"
+	"github.com/KAnggara75/conflect/internal/helper"
+)
+
+// handleProperty serves GET /api/property/{app}/{env}/{label?}?key=... (or ?prefix=...):
+// the effective value of one key, or of all keys under a prefix. Plain text is
+// returned for ?format=text or when Accept prefers text/plain.
+func (s *Server) handleProperty(w http.ResponseWriter, r *http.Request) {
+	appName, env, label, ok := parseConfigPath(r.URL.Path, "/api/property/")
+	if !ok {
+		http.Error(w, `{"error":"invalid path, expected /api/property/{app}/{env}/{label?}"}`, http.StatusBadRequest)
+		return
+	}
+	if !s.authorize(w, r, appName, env, label) {
+		return
+	}
+
+	query := r.URL.Query()
+	key, prefix := query.Get("key"), query.Get("prefix")
+	if (key == "") == (prefix == "") {
+		errors.HttpError(w, "exactly one of key or prefix is required", http.StatusBadRequest)
+		return
+	}
+
+	var plain bool
+	switch format := query.Get("format"); format {
+	case "":
+		plain = negotiateFormat(r.Header.Get("Accept")) == helper.FormatProperties
+	case "text":
+		plain = true
+	case "json":
+	default:
+		errors.HttpError(w, "invalid format "+format+", expected text or json", http.StatusBadRequest)
+		return
+	}
+
+	opts, err := loadOptionsFromQuery(r)
+	if err != nil {
+		errors.HttpError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lookup := key
+	if prefix != "" {
+		lookup = prefix
+	}
+	resp := s.configService.LookupProperties(appName, env, label, lookup, prefix != "", opts)
+
+	if resp.Error != "" {
+		errors.HttpError(w, resp.Error, http.StatusUnprocessableEntity)
+		return
+	}
+	if len(resp.Properties) == 0 {
+		errors.HttpError(w, "property "+lookup+" for "+appName+" with env "+env+" not found", http.StatusNotFound)
+		return
+	}
+
+	if !plain {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	var body []byte
+	if key != "" {
+		body = []byte(plainValue(resp.Properties[key].Value) + "\n")
+	} else {
+		values := make(map[string]any, len(resp.Properties))
+		for k, p := range resp.Properties {
+			values[k] = p.Value
+		}
+		if body, err = helper.Render(values, helper.FormatProperties); err != nil {
+			errors.HttpError(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// plainValue writes scalars as text and lists or maps as JSON.
+func plainValue(v any) string {
+	switch v.(type) {
+	case []any, map[string]any:
+		data, err := json.Marshal(v)
+		if err == nil {
+			return string(data)
+		}
+	}
+	return helper.ScalarString(v)
+}
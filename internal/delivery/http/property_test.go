@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/repository"
+	"github.com/KAnggara75/conflect/internal/service"
+)
+
+func TestHandleProperty(t *testing.T) {
+	tmpDir := t.TempDir()
+	envDir := filepath.Join(tmpDir, "main", "prod")
+	_ = os.MkdirAll(envDir, 0755)
+	_ = os.WriteFile(filepath.Join(envDir, "myapp-prod.yaml"), []byte("feature:\n  x:\n    enabled: true\n"), 0644)
+	_ = os.WriteFile(filepath.Join(envDir, "application.yaml"), []byte("feature:\n  x:\n    enabled: false\n    ratio: 0.5\n  xy: 1\n"), 0644)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := service.NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+	srv := &Server{cfg: cfg, configService: cs}
+
+	do := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		srv.handleProperty(rec, req)
+		return rec
+	}
+
+	t.Run("Single key", func(t *testing.T) {
+		rec := do("/api/property/myapp/prod/main?key=feature.x.enabled", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp dto.PropertyResponse
+		_ = json.NewDecoder(rec.Body).Decode(&resp)
+		got := resp.Properties["feature.x.enabled"]
+		if len(resp.Properties) != 1 || got.Value != true || got.Source != "myapp-prod.yaml" {
+			t.Errorf("unexpected properties %+v", resp.Properties)
+		}
+	})
+
+	t.Run("Prefix", func(t *testing.T) {
+		rec := do("/api/property/myapp/prod?prefix=feature.x", "")
+		var resp dto.PropertyResponse
+		_ = json.NewDecoder(rec.Body).Decode(&resp)
+		if len(resp.Properties) != 2 || resp.Properties["feature.x.ratio"].Value != 0.5 {
+			t.Errorf("expected feature.x.enabled and feature.x.ratio only, got %+v", resp.Properties)
+		}
+	})
+
+	t.Run("Plain text", func(t *testing.T) {
+		rec := do("/api/property/myapp/prod/main?key=feature.x.enabled", "text/plain")
+		if rec.Body.String() != "true\n" || rec.Header().Get("Content-Type") != "text/plain; charset=utf-8" {
+			t.Errorf("unexpected plain response %q (%s)", rec.Body.String(), rec.Header().Get("Content-Type"))
+		}
+
+		rec = do("/api/property/myapp/prod/main?prefix=feature.x&format=text", "")
+		if rec.Body.String() != "feature.x.enabled=true\nfeature.x.ratio=0.5\n" {
+			t.Errorf("unexpected plain prefix response %q", rec.Body.String())
+		}
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		if rec := do("/api/property/myapp/prod/main?key=feature.y", ""); rec.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Bad request", func(t *testing.T) {
+		for _, path := range []string{
+			"/api/property/myapp/prod/main",
+			"/api/property/myapp/prod/main?key=a&prefix=b",
+			"/api/property/myapp/prod/main?key=a&format=xml",
+			"/api/property/myapp?key=a",
+		} {
+			if rec := do(path, ""); rec.Code != http.StatusBadRequest {
+				t.Errorf("expected 400 for %s, got %d", path, rec.Code)
+			}
+		}
+	})
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect http
+ */
+
+package http
+
+import (
+	stderrors "errors"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/KAnggara75/conflect/internal/errors"
+)
+
+// handleResource serves GET /{app}/{env}/{label}/{path...}: a raw file from the
+// app's search directories. ?resolvePlaceholders=true expands ${key} references
+// from the app's config.
+func (s *Server) handleResource(w http.ResponseWriter, r *http.Request, appName, env, label, resourcePath string) {
+	resolve := false
+	if v := r.URL.Query().Get("resolvePlaceholders"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			errors.HttpError(w, "invalid resolvePlaceholders "+strconv.Quote(v), http.StatusBadRequest)
+			return
+		}
+		resolve = parsed
+	}
+
+	data, found, err := s.configService.LoadResource(appName, env, label, resourcePath, resolve)
+	switch {
+	case stderrors.Is(err, errors.ErrInvalidInput):
+		errors.HttpError(w, err.Error(), http.StatusBadRequest)
+		return
+	case stderrors.Is(err, errors.ErrNotFound):
+		errors.HttpError(w, "resource "+resourcePath+" for "+appName+" with env "+env+" not found", http.StatusNotFound)
+		return
+	case err != nil:
+		log.Println(err)
+		errors.HttpError(w, "failed to load resource", http.StatusInternalServerError)
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(found))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	etag := contentETag(data)
+	s.setCacheHeaders(w, etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
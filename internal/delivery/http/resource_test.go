@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/repository"
+	"github.com/KAnggara75/conflect/internal/service"
+)
+
+func TestHandleConfig_Resource(t *testing.T) {
+	tmpDir := t.TempDir()
+	envDir := filepath.Join(tmpDir, "main", "prod")
+	_ = os.MkdirAll(filepath.Join(envDir, "nginx"), 0755)
+	_ = os.WriteFile(filepath.Join(envDir, "myapp-prod.yaml"), []byte("server:\n  port: 80\n"), 0644)
+	_ = os.WriteFile(filepath.Join(envDir, "nginx", "site.conf"), []byte("listen ${server.port};"), 0644)
+	_ = os.WriteFile(filepath.Join(envDir, "logback.xml"), []byte("<configuration/>"), 0644)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := service.NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+	srv := &Server{cfg: cfg, configService: cs}
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{"Raw file", "/myapp/prod/main/nginx/site.conf", http.StatusOK, "listen ${server.port};"},
+		{"Resolved placeholders", "/myapp/prod/main/nginx/site.conf?resolvePlaceholders=true", http.StatusOK, "listen 80;"},
+		{"Invalid resolve flag", "/myapp/prod/main/nginx/site.conf?resolvePlaceholders=maybe", http.StatusBadRequest, ""},
+		{"Missing file", "/myapp/prod/main/nginx/missing.conf", http.StatusNotFound, ""},
+		{"Hidden path", "/myapp/prod/main/.git/config", http.StatusBadRequest, ""},
+		{"Trailing slash is the config", "/myapp/prod/main/", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+			srv.handleConfig(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Errorf("expected body %q, got %q", tt.body, rec.Body.String())
+			}
+		})
+	}
+
+	t.Run("Content type and conditional get", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/myapp/prod/main/logback.xml", nil)
+		rec := httptest.NewRecorder()
+		srv.handleConfig(rec, req)
+
+		if ct := rec.Header().Get("Content-Type"); !strings.Contains(ct, "xml") {
+			t.Errorf("expected an XML content type, got %q", ct)
+		}
+		etag := rec.Header().Get("ETag")
+		if etag == "" {
+			t.Fatal("expected ETag on resource response")
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/myapp/prod/main/logback.xml", nil)
+		req.Header.Set("If-None-Match", etag)
+		rec = httptest.NewRecorder()
+		srv.handleConfig(rec, req)
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("expected 304, got %d", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect http
+ */
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KAnggara75/conflect/internal/errors"
+)
+
+// Wait limits of the watch endpoint.
+const (
+	defaultWatchTimeout = 30 * time.Second
+	maxWatchTimeout     = 120 * time.Second
+)
+
+// handleWatch serves GET /api/watch/{app}/{env}/{label?}?version={version}: it blocks
+// until the config differs from the caller's version and returns it, or answers
+// 304 Not Modified once the timeout elapses.
+func (s *Server) handleWatch(w http.ResponseWriter, r *http.Request) {
+	appName, env, label, ok := parseConfigPath(r.URL.Path, "/api/watch/")
+	if !ok {
+		http.Error(w, `{"error":"invalid path, expected /api/watch/{app}/{env}/{label?}"}`, http.StatusBadRequest)
+		return
+	}
+	if !s.authorize(w, r, appName, env, label) {
+		return
+	}
+
+	timeout := defaultWatchTimeout
+	if v := r.URL.Query().Get("timeout"); v != "" {
+		secs, err := strconv.Atoi(v)
+		if err != nil || secs < 1 || time.Duration(secs)*time.Second > maxWatchTimeout {
+			errors.HttpError(w, fmt.Sprintf("invalid timeout %q, expected 1 to %d seconds", v, int(maxWatchTimeout/time.Second)), http.StatusBadRequest)
+			return
+		}
+		timeout = time.Duration(secs) * time.Second
+	}
+
+	opts, err := loadOptionsFromQuery(r)
+	if err != nil {
+		errors.HttpError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// the server's write timeout is shorter than a long poll
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout + 10*time.Second))
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	resp, changed := s.configService.WatchConfig(ctx, appName, env, label, r.URL.Query().Get("version"), opts)
+	if !changed {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case resp.Error != "":
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	case len(resp.PropertySources) == 0:
+		w.WriteHeader(http.StatusNotFound)
+		resp.Error = "config for " + appName + " with env " + env + " not found"
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/repository"
+	"github.com/KAnggara75/conflect/internal/service"
+)
+
+func TestHandleWatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	envDir := filepath.Join(tmpDir, "main", "prod")
+	_ = os.MkdirAll(envDir, 0755)
+	_ = os.WriteFile(filepath.Join(envDir, "myapp-prod.yaml"), []byte("key: value"), 0644)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main", VersionMode: config.VersionModeContent}
+	cs := service.NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+	srv := &Server{cfg: cfg, configService: cs}
+	version := cs.LoadConfig("myapp", "prod", "main").Version
+
+	tests := []struct {
+		name string
+		path string
+		code int
+	}{
+		{"Stale version", "/api/watch/myapp/prod/main?version=old", http.StatusOK},
+		{"Unchanged", "/api/watch/myapp/prod?timeout=1&version=" + version, http.StatusNotModified},
+		{"Unknown app", "/api/watch/unknown/dev/main", http.StatusNotFound},
+		{"Invalid timeout", "/api/watch/myapp/prod?timeout=600", http.StatusBadRequest},
+		{"Invalid path", "/api/watch/myapp", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+			srv.handleWatch(rec, req)
+			if rec.Code != tt.code {
+				t.Errorf("expected %d, got %d: %s", tt.code, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
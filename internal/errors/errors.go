@@ -0,0 +1,25 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect errors
+ * https://github.com/PakaiWA/PakaiWA/tree/main/internal/errors
+ */
+
+package errors
+
+import "errors"
+
+// Sentinel errors wrapped by the service layer so handlers can pick a status code
+// with errors.Is.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrInvalidInput = errors.New("invalid input")
+)
@@ -0,0 +1,167 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect helper
+ * https://github.com/PakaiWA/PakaiWA/tree/main/internal/helper
+ */
+
+package helper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseDotenv parses KEY=VALUE lines with optional `export` prefixes, single quoted
+// (literal), double quoted (escapes, multi-line) and unquoted values, `#` comments and
+// ${VAR}, ${VAR:-default} and $VAR references to keys defined earlier in the file.
+func parseDotenv(data []byte, opts ParseOptions) (map[string]any, error) {
+	src := strings.ReplaceAll(string(data), "\r\n", "\n")
+	vars := make(map[string]string)
+	res := make(map[string]any)
+
+	line := 1
+	for len(src) > 0 {
+		var current string
+		current, src, _ = strings.Cut(src, "\n")
+		startLine := line
+		line++
+
+		trimmed := strings.TrimSpace(current)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(trimmed, "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			trimmed = strings.TrimSpace(rest)
+		}
+
+		key, rawValue, ok := strings.Cut(trimmed, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", startLine)
+		}
+		rawValue = strings.TrimLeft(rawValue, " \t")
+
+		var value string
+		switch {
+		case strings.HasPrefix(rawValue, "'"):
+			end := strings.Index(rawValue[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated single-quoted value for %s", startLine, key)
+			}
+			value = rawValue[1 : end+1]
+		case strings.HasPrefix(rawValue, `"`):
+			// double-quoted values may continue over several lines
+			body := rawValue[1:]
+			end := closingQuote(body)
+			for end < 0 && len(src) > 0 {
+				var next string
+				next, src, _ = strings.Cut(src, "\n")
+				line++
+				body += "\n" + next
+				end = closingQuote(body)
+			}
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated double-quoted value for %s", startLine, key)
+			}
+			value = expandDotenv(body[:end], vars, true)
+		default:
+			if idx := strings.Index(rawValue, " #"); idx >= 0 {
+				rawValue = rawValue[:idx]
+			}
+			value = expandDotenv(strings.TrimSpace(rawValue), vars, false)
+		}
+
+		vars[key] = value
+		if opts.StrictTypes {
+			res[key] = value
+		} else {
+			res[key] = parsePrimitive(value)
+		}
+	}
+
+	return res, nil
+}
+
+// closingQuote returns the index of the first unescaped double quote in s, or -1.
+func closingQuote(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// expandDotenv resolves $VAR, ${VAR} and ${VAR:-default} against vars. Inside double
+// quotes it also handles backslash escapes; unknown variables expand to "".
+func expandDotenv(s string, vars map[string]string, escapes bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if c == '\\' && escapes && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			default: // \" \\ \$ and anything else stand for themselves
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+
+		if c != '$' || i+1 >= len(s) {
+			b.WriteByte(c)
+			continue
+		}
+
+		if s[i+1] == '{' {
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				b.WriteByte(c)
+				continue
+			}
+			expr := s[i+2 : i+2+end]
+			name, def, hasDefault := strings.Cut(expr, ":-")
+			if v, ok := vars[name]; ok && (v != "" || !hasDefault) {
+				b.WriteString(v)
+			} else {
+				b.WriteString(def)
+			}
+			i += end + 2
+			continue
+		}
+
+		j := i + 1
+		for j < len(s) && isDotenvNameChar(s[j]) {
+			j++
+		}
+		if j == i+1 {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteString(vars[s[i+1:j]])
+		i = j - 1
+	}
+	return b.String()
+}
+
+func isDotenvNameChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package helper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected map[string]any
+		wantErr  bool
+	}{
+		{
+			name:     "Basic values and comments",
+			data:     "# comment\nHOST=localhost\nPORT=8080\n\nDEBUG=true\n",
+			expected: map[string]any{"HOST": "localhost", "PORT": int64(8080), "DEBUG": true},
+		},
+		{
+			name:     "Export prefix",
+			data:     "export API_URL=https://api.example.com\nexported=1x\n",
+			expected: map[string]any{"API_URL": "https://api.example.com", "exported": "1x"},
+		},
+		{
+			name:     "Inline comment on unquoted value",
+			data:     "NAME=orders # service name\nCOLOR=#fff\n",
+			expected: map[string]any{"NAME": "orders", "COLOR": "#fff"},
+		},
+		{
+			name:     "Single quotes are literal",
+			data:     "HOST=db\nRAW='${HOST}\\n # not a comment'\n",
+			expected: map[string]any{"HOST": "db", "RAW": `${HOST}\n # not a comment`},
+		},
+		{
+			name:     "Double quotes with escapes",
+			data:     `MSG="line1\nline2\t\"quoted\" \$HOME"` + "\n",
+			expected: map[string]any{"MSG": "line1\nline2\t\"quoted\" $HOME"},
+		},
+		{
+			name:     "Multi-line double quoted value",
+			data:     "CERT=\"-----BEGIN-----\nabc\n-----END-----\"\nNEXT=x\n",
+			expected: map[string]any{"CERT": "-----BEGIN-----\nabc\n-----END-----", "NEXT": "x"},
+		},
+		{
+			name: "Interpolation",
+			data: "DB_HOST=db\nDB_PORT=5432\nURL=postgres://${DB_HOST}:$DB_PORT/app\nQUOTED=\"${DB_HOST}-x\"\nMISSING=${NOPE}\nDEFAULT=${NOPE:-fallback}\n",
+			expected: map[string]any{
+				"DB_HOST": "db",
+				"DB_PORT": int64(5432),
+				"URL":     "postgres://db:5432/app",
+				"QUOTED":  "db-x",
+				"MISSING": "",
+				"DEFAULT": "fallback",
+			},
+		},
+		{
+			name:     "CRLF line endings",
+			data:     "A=1x\r\nB=2x\r\n",
+			expected: map[string]any{"A": "1x", "B": "2x"},
+		},
+		{
+			name:    "Missing separator",
+			data:    "JUST_A_KEY\n",
+			wantErr: true,
+		},
+		{
+			name:    "Unterminated double quote",
+			data:    "A=\"open\nB=2\n",
+			wantErr: true,
+		},
+		{
+			name:    "Unterminated single quote",
+			data:    "A='open\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseDotenv([]byte(tt.data), ParseOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDotenv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("parseDotenv() = %#v, want %#v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseFile_Dotenv(t *testing.T) {
+	result, err := ParseFileWithOptions([]byte("PORT=8080\n"), ".env", ParseOptions{StrictTypes: true})
+	if err != nil {
+		t.Fatalf("ParseFileWithOptions() unexpected error: %v", err)
+	}
+	if result["PORT"] != "8080" {
+		t.Errorf("expected strict mode to keep PORT as string, got %#v", result["PORT"])
+	}
+
+	if _, err := ParseFile([]byte("oops\n"), ".env"); err == nil {
+		t.Error("expected ParseFile to report invalid dotenv line")
+	}
+}
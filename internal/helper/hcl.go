@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect helper
+ * https://github.com/PakaiWA/PakaiWA/tree/main/internal/helper
+ */
+
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// unmarshalHCL reads an HCL2 file of attributes and blocks into nested maps.
+// Block type and labels become key segments (`service "http" { port = 80 }` is
+// service.http.port); repeated blocks at the same path become a list. Attribute
+// expressions must be constant: variables and function calls are rejected.
+func unmarshalHCL(data []byte, opts ParseOptions) (map[string]any, error) {
+	file, diags := hclsyntax.ParseConfig(data, "config.hcl", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, errors.New("unexpected HCL body type")
+	}
+	return hclBodyToMap(body, opts)
+}
+
+func hclBodyToMap(body *hclsyntax.Body, opts ParseOptions) (map[string]any, error) {
+	out := make(map[string]any)
+
+	// sort attributes for deterministic error reporting
+	names := make([]string, 0, len(body.Attributes))
+	for name := range body.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		val, diags := body.Attributes[name].Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+
+		raw, err := ctyjson.Marshal(val, val.Type())
+		if err != nil {
+			return nil, err
+		}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		if opts.StrictTypes {
+			dec.UseNumber()
+		}
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		out[name] = v
+	}
+
+	for _, block := range body.Blocks {
+		m, err := hclBodyToMap(block.Body, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		// walk down type + labels, creating intermediate maps as needed
+		path := append([]string{block.Type}, block.Labels...)
+		parent := out
+		for i, segment := range path[:len(path)-1] {
+			switch next := parent[segment].(type) {
+			case nil:
+				created := make(map[string]any)
+				parent[segment] = created
+				parent = created
+			case map[string]any:
+				parent = next
+			default:
+				// a list of repeated blocks or an attribute already holds the key
+				return nil, fmt.Errorf("block %s conflicts with the value of %s", strings.Join(path, "."), strings.Join(path[:i+1], "."))
+			}
+		}
+
+		last := path[len(path)-1]
+		switch existing := parent[last].(type) {
+		case nil:
+			parent[last] = m
+		case []any:
+			parent[last] = append(existing, m)
+		default:
+			parent[last] = []any{existing, m}
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFile_HCL(t *testing.T) {
+	data := []byte(`
+region  = "ap-southeast-3"
+replicas = 3
+tags    = ["a", "b"]
+limits  = { cpu = "500m", memory = "1Gi" }
+
+service "http" {
+  port    = 8080
+  enabled = true
+}
+
+service "grpc" {
+  port = 9090
+}
+
+rule {
+  path = "/a"
+}
+
+rule {
+  path = "/b"
+}
+`)
+
+	t.Run("Attributes and blocks", func(t *testing.T) {
+		result, err := ParseFile(data, ".hcl")
+		if err != nil {
+			t.Fatalf("ParseFile() unexpected error: %v", err)
+		}
+
+		expected := map[string]any{
+			"region":               "ap-southeast-3",
+			"replicas":             float64(3),
+			"tags":                 []any{"a", "b"},
+			"limits.cpu":           "500m",
+			"limits.memory":        "1Gi",
+			"service.http.port":    float64(8080),
+			"service.http.enabled": true,
+			"service.grpc.port":    float64(9090),
+			"rule": []any{
+				map[string]any{"path": "/a"},
+				map[string]any{"path": "/b"},
+			},
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("ParseFile() = %#v, want %#v", result, expected)
+		}
+	})
+
+	t.Run("Indexed repeated blocks and strict numbers", func(t *testing.T) {
+		result, err := ParseFileWithOptions(data, ".hcl", ParseOptions{IndexedArrays: true, StrictTypes: true})
+		if err != nil {
+			t.Fatalf("ParseFileWithOptions() unexpected error: %v", err)
+		}
+		if result["rule[1].path"] != "/b" {
+			t.Errorf("expected rule[1].path, got %#v", result)
+		}
+		if result["replicas"] != json.Number("3") {
+			t.Errorf("expected json.Number replicas, got %#v", result["replicas"])
+		}
+	})
+
+	t.Run("Variables are rejected", func(t *testing.T) {
+		if _, err := ParseFile([]byte("a = var.b\n"), ".hcl"); err == nil {
+			t.Error("expected error for non-constant expression")
+		}
+	})
+
+	t.Run("Conflicting keys", func(t *testing.T) {
+		for name, src := range map[string]string{
+			"Repeated blocks": "rule {\n  path = \"/a\"\n}\nrule {\n  path = \"/b\"\n}\nrule \"x\" {\n  path = \"/c\"\n}\n",
+			"Attribute":       "service = \"http\"\nservice \"grpc\" {\n  port = 9090\n}\n",
+		} {
+			_, err := unmarshalHCL([]byte(src), ParseOptions{})
+			if err == nil || !strings.Contains(err.Error(), "conflicts with the value of") {
+				t.Errorf("%s: expected a conflict error, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("Invalid syntax", func(t *testing.T) {
+		if _, err := ParseFile([]byte("a = {\n"), ".hcl"); err == nil {
+			t.Error("expected error for invalid HCL")
+		}
+	})
+}
@@ -16,34 +16,115 @@
 package helper
 
 import (
-	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf16"
 
 	"gopkg.in/yaml.v3"
 )
 
-func ParseFile(data []byte, ext string) (map[string]any, error) {
+// ParseOptions controls how parsed values are typed.
+type ParseOptions struct {
+	// StrictTypes keeps .properties values as strings and YAML/JSON numbers as
+	// json.Number with their exact textual representation.
+	StrictTypes bool
+	// IndexedArrays flattens lists into Spring-style key[0].name keys.
+	IndexedArrays bool
+}
+
+// Document is one document of a config file. Profiles holds its activation
+// expression from spring.config.activate.on-profile (or the older spring.profiles);
+// an empty expression means the document is always active.
+type Document struct {
+	Profiles string
+	Source   map[string]any
+}
+
+// Profile activation keys recognised in YAML documents.
+const (
+	activateOnProfileKey = "spring.config.activate.on-profile"
+	legacyProfilesKey    = "spring.profiles"
+)
+
+// ParseFile parses a config file into dot.notation keys, merging the documents that
+// are active for profiles as MatchesProfiles decides, later documents overriding
+// earlier ones. Documents with a profile activation condition are dropped when no
+// profile satisfies it, whether or not the file has other documents.
+func ParseFile(data []byte, ext string, profiles ...string) (map[string]any, error) {
+	return ParseFileWithOptions(data, ext, ParseOptions{}, profiles...)
+}
+
+func ParseFileWithOptions(data []byte, ext string, opts ParseOptions, profiles ...string) (map[string]any, error) {
+	docs, err := ParseDocuments(data, ext, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	out := make(map[string]any)
+	for _, doc := range docs {
+		active, err := MatchesProfiles(doc.Profiles, profiles)
+		if err != nil {
+			return nil, err
+		}
+		if !active {
+			continue
+		}
+		for k, v := range doc.Source {
+			out[k] = v
+		}
+	}
+	return out, nil
+}
 
+// ParseDocuments parses a config file into its documents, in file order. Only YAML
+// files can hold more than one document.
+func ParseDocuments(data []byte, ext string, opts ParseOptions) ([]Document, error) {
 	switch ext {
 	case ".yaml", ".yml":
-		var m map[string]any
-		if err := yaml.Unmarshal(data, &m); err != nil {
-			return nil, fmt.Errorf("yaml unmarshal: %w", err)
-		}
-		flattenMap("", m, out)
+		return parseYAMLDocuments(data, opts)
+	}
+
+	out := make(map[string]any)
+
+	switch ext {
 	case ".json":
-		var m map[string]any
-		if err := json.Unmarshal(data, &m); err != nil {
+		m, err := unmarshalJSON(data, opts)
+		if err != nil {
 			return nil, fmt.Errorf("json unmarshal: %w", err)
 		}
-		flattenMap("", m, out)
+		flattenMap("", m, out, opts.IndexedArrays)
+	case ".toml":
+		m, err := unmarshalTOML(data)
+		if err != nil {
+			return nil, fmt.Errorf("toml unmarshal: %w", err)
+		}
+		flattenMap("", m, out, opts.IndexedArrays)
+	case ".hcl":
+		m, err := unmarshalHCL(data, opts)
+		if err != nil {
+			return nil, fmt.Errorf("hcl parse: %w", err)
+		}
+		flattenMap("", m, out, opts.IndexedArrays)
+	case ".env":
+		m, err := parseDotenv(data, opts)
+		if err != nil {
+			return nil, fmt.Errorf("dotenv parse: %w", err)
+		}
+		for k, v := range m {
+			out[k] = v
+		}
 	case ".properties":
-		m := parseProperties(data)
+		m, err := parseProperties(data, opts)
+		if err != nil {
+			return nil, fmt.Errorf("properties parse: %w", err)
+		}
 		for k, v := range m {
 			out[k] = v
 		}
@@ -51,63 +132,388 @@ func ParseFile(data []byte, ext string) (map[string]any, error) {
 		return nil, fmt.Errorf("unsupported ext: %s", ext)
 	}
 
-	return out, nil
+	return []Document{{Source: out}}, nil
+}
+
+// parseYAMLDocuments reads every `---` separated document. Empty documents are skipped.
+func parseYAMLDocuments(data []byte, opts ParseOptions) ([]Document, error) {
+	var docs []Document
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var node yaml.Node
+		err := dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("yaml unmarshal: %w", err)
+		}
+
+		m, err := yamlNodeToMap(&node, opts)
+		if err != nil {
+			return nil, fmt.Errorf("yaml unmarshal: %w", err)
+		}
+		if m == nil {
+			continue
+		}
+
+		out := make(map[string]any)
+		flattenMap("", m, out, opts.IndexedArrays)
+		docs = append(docs, Document{Profiles: activationProfiles(out), Source: out})
+	}
+
+	if docs == nil {
+		// keep the single empty source an empty file used to produce
+		docs = []Document{{Source: map[string]any{}}}
+	}
+	return docs, nil
+}
+
+// activationProfiles returns the profile expression a flattened document is
+// restricted to. Lists (raw or indexed) are joined with commas, meaning any of them.
+func activationProfiles(flat map[string]any) string {
+	for _, key := range []string{activateOnProfileKey, legacyProfilesKey} {
+		if v, ok := flat[key]; ok {
+			return profileString(v)
+		}
+
+		var parts []string
+		for i := 0; ; i++ {
+			v, ok := flat[key+"["+strconv.Itoa(i)+"]"]
+			if !ok {
+				break
+			}
+			parts = append(parts, profileString(v))
+		}
+		if len(parts) > 0 {
+			return strings.Join(parts, ",")
+		}
+	}
+	return ""
+}
+
+func profileString(v any) string {
+	if list, ok := v.([]any); ok {
+		parts := make([]string, 0, len(list))
+		for _, item := range list {
+			parts = append(parts, fmt.Sprintf("%v", item))
+		}
+		return strings.Join(parts, ",")
+	}
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// yamlNodeToMap converts one decoded YAML document into a map. It returns nil
+// for an empty document.
+func yamlNodeToMap(node *yaml.Node, opts ParseOptions) (map[string]any, error) {
+	if !opts.StrictTypes {
+		var m map[string]any
+		err := node.Decode(&m)
+		return m, err
+	}
+
+	v, err := strictYAMLValue(node)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("document root is %T, not a mapping", v)
+	}
+	return m, nil
+}
+
+func unmarshalJSON(data []byte, opts ParseOptions) (map[string]any, error) {
+	var m map[string]any
+	if !opts.StrictTypes {
+		err := json.Unmarshal(data, &m)
+		return m, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected data after top-level value")
+	}
+	return m, nil
+}
+
+// jsonNumberPattern matches number literals that are valid JSON.
+var jsonNumberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// strictYAMLValue converts a YAML node like yaml.v3 does, except that int and float
+// scalars written as JSON number literals become json.Number instead of int/float64.
+func strictYAMLValue(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return strictYAMLValue(node.Content[0])
+	case yaml.AliasNode:
+		return strictYAMLValue(node.Alias)
+	case yaml.SequenceNode:
+		list := make([]any, 0, len(node.Content))
+		for _, item := range node.Content {
+			v, err := strictYAMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case yaml.MappingNode:
+		m := make(map[string]any, len(node.Content)/2)
+		var merged []map[string]any
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			v, err := strictYAMLValue(valueNode)
+			if err != nil {
+				return nil, err
+			}
+			if keyNode.ShortTag() == "!!merge" {
+				switch mv := v.(type) {
+				case map[string]any:
+					merged = append(merged, mv)
+				case []any:
+					for _, item := range mv {
+						if im, ok := item.(map[string]any); ok {
+							merged = append(merged, im)
+						}
+					}
+				}
+				continue
+			}
+			m[keyNode.Value] = v
+		}
+		// explicit keys win over merged ones, earlier merge sources over later ones
+		for _, src := range merged {
+			for k, v := range src {
+				if _, ok := m[k]; !ok {
+					m[k] = v
+				}
+			}
+		}
+		return m, nil
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!int", "!!float":
+			if jsonNumberPattern.MatchString(node.Value) {
+				return json.Number(node.Value), nil
+			}
+		}
+	}
+
+	var v any
+	if err := node.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
 }
 
-// flattenMap flattens nested maps into dot.notation keys
-func flattenMap(prefix string, cur any, out map[string]any) {
+// flattenMap flattens nested maps into dot.notation keys. With indexed set, lists
+// are flattened Spring-style into key[0], key[1].name, ... instead of kept as-is.
+func flattenMap(prefix string, cur any, out map[string]any, indexed bool) {
 	switch t := cur.(type) {
 	case map[string]any:
 		for k, v := range t {
-			var key string
-			if prefix == "" {
-				key = k
-			} else {
-				key = prefix + "." + k
-			}
-			flattenMap(key, v, out)
+			flattenMap(joinKey(prefix, k), v, out, indexed)
 		}
 	case map[any]any: // in case yaml produced any keys
 		for kk, vv := range t {
 			k := fmt.Sprintf("%v", kk)
-			var key string
-			if prefix == "" {
-				key = k
-			} else {
-				key = prefix + "." + k
-			}
-			flattenMap(key, vv, out)
+			flattenMap(joinKey(prefix, k), vv, out, indexed)
 		}
 	case []any:
-		// keep slices as-is (consumer can interpret), placed at prefix key
-		out[prefix] = t
+		if !indexed {
+			// keep slices as-is (consumer can interpret), placed at prefix key
+			out[prefix] = t
+			return
+		}
+		// Spring's YamlProcessor maps an empty list to an empty string
+		if len(t) == 0 {
+			out[prefix] = ""
+			return
+		}
+		for i, v := range t {
+			flattenMap(prefix+"["+strconv.Itoa(i)+"]", v, out, indexed)
+		}
 	default:
 		out[prefix] = t
 	}
 }
 
-func parseProperties(data []byte) map[string]any {
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// propertyWhitespace is the whitespace java.util.Properties skips around keys and separators.
+const propertyWhitespace = " \t\f"
+
+// parseProperties implements the java.util.Properties load grammar: backslash line
+// continuations, \uXXXX and character escapes, escaped separators in keys and
+// '=', ':' or whitespace as the key/value separator.
+func parseProperties(data []byte, opts ParseOptions) (map[string]any, error) {
 	res := make(map[string]any)
-	s := bufio.NewScanner(bytes.NewReader(data))
-	for s.Scan() {
-		line := strings.TrimSpace(s.Text())
-		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
-			continue
+	for _, line := range logicalPropertyLines(string(data)) {
+		rawKey, rawValue := splitPropertyLine(line)
+
+		key, err := unescapeProperty(rawKey)
+		if err != nil {
+			return nil, err
 		}
-		// support key:value and key=value
-		var kv []string
-		if strings.Contains(line, "=") {
-			kv = strings.SplitN(line, "=", 2)
-		} else if strings.Contains(line, ":") {
-			kv = strings.SplitN(line, ":", 2)
+		value, err := unescapeProperty(rawValue)
+		if err != nil {
+			return nil, err
+		}
+		if opts.StrictTypes {
+			res[key] = value
 		} else {
+			res[key] = parsePrimitive(value)
+		}
+	}
+	return res, nil
+}
+
+// logicalPropertyLines joins natural lines ending in an odd number of backslashes
+// with the following line, dropping comments and blank lines. Escapes are kept.
+func logicalPropertyLines(data string) []string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\r", "\n")
+
+	var (
+		lines        []string
+		current      strings.Builder
+		continuation bool
+	)
+	for _, natural := range strings.Split(data, "\n") {
+		natural = strings.TrimLeft(natural, propertyWhitespace)
+		if !continuation {
+			// a comment line is never continued, even if it ends with a backslash
+			if natural == "" || natural[0] == '#' || natural[0] == '!' {
+				continue
+			}
+		}
+
+		trailing := len(natural) - len(strings.TrimRight(natural, "\\"))
+		if trailing%2 == 1 {
+			current.WriteString(natural[:len(natural)-1])
+			continuation = true
+			continue
+		}
+
+		current.WriteString(natural)
+		lines = append(lines, current.String())
+		current.Reset()
+		continuation = false
+	}
+	if continuation {
+		lines = append(lines, current.String())
+	}
+	return lines
+}
+
+// decodeUnicodeEscape decodes the XXXX of a \uXXXX escape, combining a UTF-16
+// surrogate pair written as two escapes. It returns the number of bytes consumed.
+func decodeUnicodeEscape(s string) (rune, int, error) {
+	if len(s) < 4 {
+		return 0, 0, errors.New("malformed \\uxxxx encoding")
+	}
+	code, err := strconv.ParseUint(s[:4], 16, 16)
+	if err != nil {
+		return 0, 0, errors.New("malformed \\uxxxx encoding")
+	}
+
+	r := rune(code)
+	if utf16.IsSurrogate(r) && len(s) >= 10 && s[4:6] == "\\u" {
+		if low, err := strconv.ParseUint(s[6:10], 16, 16); err == nil {
+			if pair := utf16.DecodeRune(r, rune(low)); pair != unicode.ReplacementChar {
+				return pair, 10, nil
+			}
+		}
+	}
+	return r, 4, nil
+}
+
+// splitPropertyLine splits a logical line at the first unescaped '=', ':' or
+// whitespace. Whitespace around the separator is not part of the value.
+func splitPropertyLine(line string) (key, value string) {
+	keyEnd := len(line)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' {
+			i++ // skip the escaped character
+			continue
+		}
+		if c == '=' || c == ':' || strings.IndexByte(propertyWhitespace, c) >= 0 {
+			keyEnd = i
+			break
+		}
+	}
+
+	i := keyEnd
+	for i < len(line) && strings.IndexByte(propertyWhitespace, line[i]) >= 0 {
+		i++
+	}
+	if i < len(line) && (line[i] == '=' || line[i] == ':') {
+		i++
+		for i < len(line) && strings.IndexByte(propertyWhitespace, line[i]) >= 0 {
+			i++
+		}
+	}
+	return line[:keyEnd], line[i:]
+}
+
+// unescapeProperty resolves \t, \n, \r, \f and \uXXXX escapes. Any other escaped
+// character stands for itself, as in java.util.Properties.
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			b.WriteByte(c)
 			continue
 		}
-		k := strings.TrimSpace(kv[0])
-		v := strings.TrimSpace(kv[1])
-		res[k] = parsePrimitive(v)
+
+		i++
+		if i >= len(s) {
+			break
+		}
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			r, n, err := decodeUnicodeEscape(s[i+1:])
+			if err != nil {
+				return "", fmt.Errorf("%w in %q", err, s)
+			}
+			b.WriteRune(r)
+			i += n
+		default:
+			b.WriteByte(s[i])
+		}
 	}
-	return res
+	return b.String(), nil
 }
 
 func parsePrimitive(s string) any {
@@ -12,6 +12,7 @@
 package helper
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 )
@@ -271,10 +272,379 @@ key2=value2
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := parseProperties(tt.data)
+			result, err := parseProperties(tt.data, ParseOptions{})
+			if err != nil {
+				t.Fatalf("parseProperties() unexpected error: %v", err)
+			}
 			if !reflect.DeepEqual(result, tt.expected) {
 				t.Errorf("parseProperties() = %v, want %v", result, tt.expected)
 			}
 		})
 	}
 }
+
+// TestParseProperties_JavaGrammar mirrors how java.util.Properties.load treats each input.
+func TestParseProperties_JavaGrammar(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected map[string]any
+	}{
+		{
+			name:     "Line continuation",
+			data:     "fruits=apple, banana, \\\n        pear, cantaloupe\n",
+			expected: map[string]any{"fruits": "apple, banana, pear, cantaloupe"},
+		},
+		{
+			name:     "Continuation with CRLF",
+			data:     "key=one \\\r\n  two\r\nnext=x\r\n",
+			expected: map[string]any{"key": "one two", "next": "x"},
+		},
+		{
+			name:     "Even trailing backslashes do not continue",
+			data:     "path=C:\\\\\nnext=x\n",
+			expected: map[string]any{"path": "C:\\", "next": "x"},
+		},
+		{
+			name:     "Continuation at end of file",
+			data:     "key=value\\",
+			expected: map[string]any{"key": "value"},
+		},
+		{
+			name:     "Continued line starting with hash is not a comment",
+			data:     "key=a\\\n#b\n",
+			expected: map[string]any{"key": "a#b"},
+		},
+		{
+			name:     "Comment line is never continued",
+			data:     "# comment \\\nkey=value\n",
+			expected: map[string]any{"key": "value"},
+		},
+		{
+			name:     "Unicode escapes",
+			data:     "greeting=\\u0048\\u0069\nemoji=\\uD83D\\uDE00\n",
+			expected: map[string]any{"greeting": "Hi", "emoji": "😀"},
+		},
+		{
+			name:     "Character escapes",
+			data:     "tab=a\\tb\nnewline=a\\nb\nother=\\q\n",
+			expected: map[string]any{"tab": "a\tb", "newline": "a\nb", "other": "q"},
+		},
+		{
+			name:     "Escaped separators in key",
+			data:     "a\\=b=c\nx\\:y:z\nwith\\ space=1x\n",
+			expected: map[string]any{"a=b": "c", "x:y": "z", "with space": "1x"},
+		},
+		{
+			name:     "Whitespace-only separator",
+			data:     "key value\nkey2\t\tvalue two\n",
+			expected: map[string]any{"key": "value", "key2": "value two"},
+		},
+		{
+			name:     "Whitespace around separator",
+			data:     "key   =   value\nother :  x\n",
+			expected: map[string]any{"key": "value", "other": "x"},
+		},
+		{
+			name:     "Value containing separators",
+			data:     "url=jdbc:postgresql://db:5432/app?a=b\nratio:1=2\n",
+			expected: map[string]any{"url": "jdbc:postgresql://db:5432/app?a=b", "ratio": "1=2"},
+		},
+		{
+			name:     "Equals before colon",
+			data:     "k=v:w\n",
+			expected: map[string]any{"k": "v:w"},
+		},
+		{
+			name:     "Key without value",
+			data:     "cheeses\nempty=\n",
+			expected: map[string]any{"cheeses": "", "empty": ""},
+		},
+		{
+			name:     "Trailing whitespace is kept in values",
+			data:     "key=value  \n",
+			expected: map[string]any{"key": "value  "},
+		},
+		{
+			name:     "Leading whitespace and form feed",
+			data:     "   \f key=value\n",
+			expected: map[string]any{"key": "value"},
+		},
+		{
+			name:     "Duplicate key last wins",
+			data:     "key=first\nkey=second\n",
+			expected: map[string]any{"key": "second"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseProperties([]byte(tt.data), ParseOptions{})
+			if err != nil {
+				t.Fatalf("parseProperties() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("parseProperties() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseProperties_MalformedUnicode(t *testing.T) {
+	for _, data := range []string{"key=\\u12", "key=\\uZZZZ", "\\u00G1=value"} {
+		if _, err := parseProperties([]byte(data), ParseOptions{}); err == nil {
+			t.Errorf("expected error for malformed escape in %q", data)
+		}
+	}
+
+	if _, err := ParseFile([]byte("key=\\u12"), ".properties"); err == nil {
+		t.Error("expected ParseFile to report malformed escape")
+	}
+}
+
+func TestParseFileWithOptions_StrictTypes(t *testing.T) {
+	strict := ParseOptions{StrictTypes: true}
+
+	tests := []struct {
+		name     string
+		data     string
+		ext      string
+		expected map[string]any
+		wantErr  bool
+	}{
+		{
+			name: "Properties stay strings",
+			data: "zip=01234\nversion=1.10\nflag=T\nenabled=true\n",
+			ext:  ".properties",
+			expected: map[string]any{
+				"zip":     "01234",
+				"version": "1.10",
+				"flag":    "T",
+				"enabled": "true",
+			},
+		},
+		{
+			name: "YAML numbers keep their text",
+			data: "version: 1.10\nbig: 12345678901234567890\nport: 8080\nname: app\nenabled: true\nzip: '01234'\nempty:\n",
+			ext:  ".yaml",
+			expected: map[string]any{
+				"version": json.Number("1.10"),
+				"big":     json.Number("12345678901234567890"),
+				"port":    json.Number("8080"),
+				"name":    "app",
+				"enabled": true,
+				"zip":     "01234",
+				"empty":   nil,
+			},
+		},
+		{
+			name: "YAML nested lists, anchors and merge keys",
+			data: "base: &base\n  timeout: 1.50\n  retries: 3\nsvc:\n  <<: *base\n  retries: 5\n  ports: [80, 443]\n",
+			ext:  ".yml",
+			expected: map[string]any{
+				"base.timeout": json.Number("1.50"),
+				"base.retries": json.Number("3"),
+				"svc.timeout":  json.Number("1.50"),
+				"svc.retries":  json.Number("5"),
+				"svc.ports":    []any{json.Number("80"), json.Number("443")},
+			},
+		},
+		{
+			name:     "YAML non JSON number literal",
+			data:     "hex: 0x1F\n",
+			ext:      ".yaml",
+			expected: map[string]any{"hex": 31},
+		},
+		{
+			name:     "YAML empty document",
+			data:     "",
+			ext:      ".yaml",
+			expected: map[string]any{},
+		},
+		{
+			name:    "YAML scalar root",
+			data:    "just a string",
+			ext:     ".yaml",
+			wantErr: true,
+		},
+		{
+			name: "JSON numbers keep their text",
+			data: `{"version": 1.10, "big": 12345678901234567890, "nested": {"ratio": 1e3}}`,
+			ext:  ".json",
+			expected: map[string]any{
+				"version":      json.Number("1.10"),
+				"big":          json.Number("12345678901234567890"),
+				"nested.ratio": json.Number("1e3"),
+			},
+		},
+		{
+			name:    "JSON trailing data",
+			data:    `{"a": 1} {"b": 2}`,
+			ext:     ".json",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseFileWithOptions([]byte(tt.data), tt.ext, strict)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFileWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ParseFileWithOptions() = %#v, want %#v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseFileWithOptions_IndexedArrays(t *testing.T) {
+	indexed := ParseOptions{IndexedArrays: true}
+
+	tests := []struct {
+		name     string
+		data     string
+		ext      string
+		expected map[string]any
+	}{
+		{
+			name: "List of maps",
+			data: "servers:\n  - host: a\n    port: 80\n  - host: b\n",
+			ext:  ".yaml",
+			expected: map[string]any{
+				"servers[0].host": "a",
+				"servers[0].port": 80,
+				"servers[1].host": "b",
+			},
+		},
+		{
+			name: "Nested lists",
+			data: "matrix:\n  - [1, 2]\n  - [3]\nempty: []\n",
+			ext:  ".yml",
+			expected: map[string]any{
+				"matrix[0][0]": 1,
+				"matrix[0][1]": 2,
+				"matrix[1][0]": 3,
+				"empty":        "",
+			},
+		},
+		{
+			name: "Lists inside maps inside lists",
+			data: `{"routes": [{"id": "r1", "predicates": ["Path=/a", "Method=GET"]}]}`,
+			ext:  ".json",
+			expected: map[string]any{
+				"routes[0].id":            "r1",
+				"routes[0].predicates[0]": "Path=/a",
+				"routes[0].predicates[1]": "Method=GET",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseFileWithOptions([]byte(tt.data), tt.ext, indexed)
+			if err != nil {
+				t.Fatalf("ParseFileWithOptions() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ParseFileWithOptions() = %#v, want %#v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseDocuments_MultiDocumentYAML(t *testing.T) {
+	data := []byte(`
+server:
+  port: 8080
+---
+spring:
+  config:
+    activate:
+      on-profile: prod
+server:
+  port: 80
+---
+spring:
+  profiles: [dev, test]
+debug: true
+---
+`)
+
+	docs, err := ParseDocuments(data, ".yaml", ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseDocuments() unexpected error: %v", err)
+	}
+	if len(docs) != 3 {
+		t.Fatalf("expected 3 non-empty documents, got %d", len(docs))
+	}
+
+	wantProfiles := []string{"", "prod", "dev,test"}
+	for i, want := range wantProfiles {
+		if docs[i].Profiles != want {
+			t.Errorf("document %d profiles = %q, want %q", i, docs[i].Profiles, want)
+		}
+	}
+	if docs[1].Source["server.port"] != 80 {
+		t.Errorf("expected second document server.port 80, got %v", docs[1].Source["server.port"])
+	}
+
+	t.Run("Indexed profile list", func(t *testing.T) {
+		docs, err := ParseDocuments(data, ".yaml", ParseOptions{IndexedArrays: true})
+		if err != nil {
+			t.Fatalf("ParseDocuments() unexpected error: %v", err)
+		}
+		if docs[2].Profiles != "dev,test" {
+			t.Errorf("expected indexed profiles to be joined, got %q", docs[2].Profiles)
+		}
+	})
+
+	t.Run("ParseFile keeps unconditional documents", func(t *testing.T) {
+		result, err := ParseFile(data, ".yaml")
+		if err != nil {
+			t.Fatalf("ParseFile() unexpected error: %v", err)
+		}
+		expected := map[string]any{"server.port": 8080}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("ParseFile() = %v, want %v", result, expected)
+		}
+	})
+
+	t.Run("ParseFile selects documents by profile", func(t *testing.T) {
+		result, err := ParseFile(data, ".yaml", "dev")
+		if err != nil {
+			t.Fatalf("ParseFile() unexpected error: %v", err)
+		}
+		expected := map[string]any{"server.port": 8080, "spring.profiles": []any{"dev", "test"}, "debug": true}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("ParseFile() = %v, want %v", result, expected)
+		}
+	})
+
+	t.Run("ParseFile drops a single inactive document", func(t *testing.T) {
+		single := []byte("spring.profiles: prod\nserver:\n  port: 80\n")
+		if result, err := ParseFile(single, ".yaml"); err != nil || len(result) != 0 {
+			t.Errorf("ParseFile() = %v (err %v), want no keys without the prod profile", result, err)
+		}
+		result, err := ParseFile(single, ".yaml", "prod")
+		if err != nil {
+			t.Fatalf("ParseFile() unexpected error: %v", err)
+		}
+		if expected := map[string]any{"spring.profiles": "prod", "server.port": 80}; !reflect.DeepEqual(result, expected) {
+			t.Errorf("ParseFile() = %v, want %v", result, expected)
+		}
+	})
+
+	t.Run("Non YAML is a single document", func(t *testing.T) {
+		docs, err := ParseDocuments([]byte("a=1\n"), ".properties", ParseOptions{})
+		if err != nil || len(docs) != 1 || docs[0].Profiles != "" {
+			t.Errorf("expected one unconditional document, got %v (err %v)", docs, err)
+		}
+	})
+
+	t.Run("Invalid later document", func(t *testing.T) {
+		if _, err := ParseDocuments([]byte("a: 1\n---\nb: [\n"), ".yaml", ParseOptions{}); err == nil {
+			t.Error("expected error for invalid second document")
+		}
+	})
+}
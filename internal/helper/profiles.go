@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect helper
+ * https://github.com/PakaiWA/PakaiWA/tree/main/internal/helper
+ */
+
+package helper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MatchesProfiles evaluates a Spring profile expression against the active profiles.
+// It supports single names, `!` negation, `&` and `|` operators, parentheses and
+// comma-separated lists, which match when any element matches. An empty expression
+// always matches.
+func MatchesProfiles(expr string, active []string) (bool, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return true, nil
+	}
+
+	set := make(map[string]struct{}, len(active))
+	for _, p := range active {
+		set[p] = struct{}{}
+	}
+
+	for _, part := range splitTopLevel(expr, ',') {
+		p := &profileParser{src: part}
+		ok, err := p.parseOr(set)
+		if err != nil {
+			return false, fmt.Errorf("invalid profile expression %q: %w", expr, err)
+		}
+		if p.skipSpace(); p.pos != len(p.src) {
+			return false, fmt.Errorf("invalid profile expression %q: unexpected %q", expr, p.src[p.pos:])
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// splitTopLevel splits s on sep outside of parentheses.
+func splitTopLevel(s string, sep byte) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+type profileParser struct {
+	src string
+	pos int
+}
+
+func (p *profileParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *profileParser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *profileParser) parseOr(set map[string]struct{}) (bool, error) {
+	left, err := p.parseAnd(set)
+	if err != nil {
+		return false, err
+	}
+	for p.peek() == '|' {
+		p.pos++
+		right, err := p.parseAnd(set)
+		if err != nil {
+			return false, err
+		}
+		left = left || right
+	}
+	return left, nil
+}
+
+func (p *profileParser) parseAnd(set map[string]struct{}) (bool, error) {
+	left, err := p.parseUnary(set)
+	if err != nil {
+		return false, err
+	}
+	for p.peek() == '&' {
+		p.pos++
+		right, err := p.parseUnary(set)
+		if err != nil {
+			return false, err
+		}
+		left = left && right
+	}
+	return left, nil
+}
+
+func (p *profileParser) parseUnary(set map[string]struct{}) (bool, error) {
+	switch p.peek() {
+	case '!':
+		p.pos++
+		v, err := p.parseUnary(set)
+		return !v, err
+	case '(':
+		p.pos++
+		v, err := p.parseOr(set)
+		if err != nil {
+			return false, err
+		}
+		if p.peek() != ')' {
+			return false, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return v, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune("!&|() \t", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	if start == p.pos {
+		return false, fmt.Errorf("expected profile name at position %d", start)
+	}
+	_, ok := set[p.src[start:p.pos]]
+	return ok, nil
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package helper
+
+import "testing"
+
+func TestMatchesProfiles(t *testing.T) {
+	active := []string{"prod", "eu"}
+
+	tests := []struct {
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{expr: "", want: true},
+		{expr: "prod", want: true},
+		{expr: "dev", want: false},
+		{expr: "!dev", want: true},
+		{expr: "!prod", want: false},
+		{expr: "dev,prod", want: true},
+		{expr: "dev, staging", want: false},
+		{expr: "prod & eu", want: true},
+		{expr: "prod & us", want: false},
+		{expr: "dev | eu", want: true},
+		{expr: "(dev | prod) & !us", want: true},
+		{expr: "!(prod & eu)", want: false},
+		{expr: "prod &", wantErr: true},
+		{expr: "(prod", wantErr: true},
+		{expr: "prod eu", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := MatchesProfiles(tt.expr, active)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("MatchesProfiles(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("MatchesProfiles(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
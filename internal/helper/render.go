@@ -0,0 +1,342 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect helper
+ * https://github.com/PakaiWA/PakaiWA/tree/main/internal/helper
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats for a merged config.
+const (
+	FormatYAML       = "yaml"
+	FormatJSON       = "json"
+	FormatProperties = "properties"
+	FormatDotenv     = "env"
+)
+
+// maxListIndex bounds the [i] indexes accepted when re-nesting flat keys.
+const maxListIndex = 1 << 16
+
+// Render writes a flat key/value map in the given format. YAML and JSON are
+// re-nested from the dotted and indexed keys; .properties and .env stay flat, with
+// any list or map values flattened Spring-style.
+func Render(flat map[string]any, format string) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		nested, err := Unflatten(flat)
+		if err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(yamlNumbers(nested))
+	case FormatJSON:
+		nested, err := Unflatten(flat)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.MarshalIndent(nested, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case FormatProperties:
+		return renderProperties(flat), nil
+	case FormatDotenv:
+		return renderDotenv(flat), nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// keySegment is one step of a flat key: a map key or a list index.
+type keySegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// splitKeyPath splits servers[0].host into servers, [0], host. Bracketed segments
+// that are not numbers (Spring's map[key] syntax) become map keys.
+func splitKeyPath(key string) []keySegment {
+	var segs []keySegment
+	for _, part := range strings.Split(key, ".") {
+		name := part
+		rest := ""
+		if i := strings.IndexByte(part, '['); i > 0 && strings.HasSuffix(part, "]") {
+			name, rest = part[:i], part[i:]
+		}
+		segs = append(segs, keySegment{key: name})
+
+		for rest != "" {
+			end := strings.IndexByte(rest, ']')
+			if !strings.HasPrefix(rest, "[") || end < 0 {
+				segs[len(segs)-1].key += rest
+				break
+			}
+			inner := rest[1:end]
+			if n, err := strconv.Atoi(inner); err == nil && n >= 0 {
+				segs = append(segs, keySegment{index: n, isIndex: true})
+			} else {
+				segs = append(segs, keySegment{key: inner})
+			}
+			rest = rest[end+1:]
+		}
+	}
+	return segs
+}
+
+// Unflatten rebuilds nested maps and lists from dotted and indexed keys. It fails
+// when a key needs a scalar to be a map or list, e.g. with both `a` and `a.b` set.
+func Unflatten(flat map[string]any) (map[string]any, error) {
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var root any = make(map[string]any)
+	for _, k := range keys {
+		var err error
+		root, err = setKeyPath(root, splitKeyPath(k), flat[k], k)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return root.(map[string]any), nil
+}
+
+func setKeyPath(cur any, segs []keySegment, value any, key string) (any, error) {
+	if len(segs) == 0 {
+		if cur != nil {
+			return nil, fmt.Errorf("key %s conflicts with another key", key)
+		}
+		return value, nil
+	}
+
+	seg := segs[0]
+	if seg.isIndex {
+		var list []any
+		switch c := cur.(type) {
+		case nil:
+		case []any:
+			list = c
+		default:
+			return nil, fmt.Errorf("key %s conflicts with another key", key)
+		}
+		if seg.index >= maxListIndex {
+			return nil, fmt.Errorf("key %s: list index too large", key)
+		}
+		for len(list) <= seg.index {
+			list = append(list, nil)
+		}
+		v, err := setKeyPath(list[seg.index], segs[1:], value, key)
+		if err != nil {
+			return nil, err
+		}
+		list[seg.index] = v
+		return list, nil
+	}
+
+	var m map[string]any
+	switch c := cur.(type) {
+	case nil:
+		m = make(map[string]any)
+	case map[string]any:
+		m = c
+	default:
+		return nil, fmt.Errorf("key %s conflicts with another key", key)
+	}
+	v, err := setKeyPath(m[seg.key], segs[1:], value, key)
+	if err != nil {
+		return nil, err
+	}
+	m[seg.key] = v
+	return m, nil
+}
+
+// yamlNumber writes a json.Number as an unquoted YAML number.
+type yamlNumber json.Number
+
+func (n yamlNumber) MarshalYAML() (any, error) {
+	tag := "!!int"
+	if strings.ContainsAny(string(n), ".eE") {
+		tag = "!!float"
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: string(n)}, nil
+}
+
+// yamlNumbers replaces json.Number values, which yaml.v3 would quote as strings.
+func yamlNumbers(v any) any {
+	switch t := v.(type) {
+	case json.Number:
+		return yamlNumber(t)
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, item := range t {
+			out[k] = yamlNumbers(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, item := range t {
+			out[i] = yamlNumbers(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// flatScalars flattens list and map values into indexed keys and returns the keys
+// in sorted order.
+func flatScalars(flat map[string]any) (map[string]any, []string) {
+	out := make(map[string]any, len(flat))
+	for k, v := range flat {
+		flattenMap(k, v, out, true)
+	}
+	keys := make([]string, 0, len(out))
+	for k := range out {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return out, keys
+}
+
+// ScalarString formats a scalar config value as plain text; nil is "".
+func ScalarString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// renderProperties writes sorted key=value lines in java.util.Properties syntax.
+func renderProperties(flat map[string]any) []byte {
+	values, keys := flatScalars(flat)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(escapeProperty(k, true))
+		b.WriteByte('=')
+		b.WriteString(escapeProperty(ScalarString(values[k]), false))
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+// escapeProperty escapes s for a .properties key or value. Non-ASCII characters are
+// written as \uXXXX so the output also loads as ISO-8859-1.
+func escapeProperty(s string, isKey bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '=', ':', '#', '!', ' ':
+			// separators only need escaping in keys, comment markers and
+			// whitespace only where a value starts
+			if isKey || i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		default:
+			if r < 0x20 || r > 0x7e {
+				if r1, r2 := utf16.EncodeRune(r); r1 != unicode.ReplacementChar {
+					fmt.Fprintf(&b, `\u%04X\u%04X`, r1, r2)
+				} else {
+					fmt.Fprintf(&b, `\u%04X`, r)
+				}
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// renderDotenv writes sorted KEY="value" lines. Keys are converted the way Spring's
+// relaxed binding reads environment variables: dashes are dropped and other
+// separators become underscores, so servers[0].max-size is SERVERS_0_MAXSIZE.
+func renderDotenv(flat map[string]any) []byte {
+	values, keys := flatScalars(flat)
+
+	var (
+		b    strings.Builder
+		seen = make(map[string]string, len(keys))
+	)
+	for _, k := range keys {
+		name := dotenvName(k)
+		if prev, ok := seen[name]; ok {
+			log.Printf("skip %s in .env output: %s already maps to %s", k, prev, name)
+			continue
+		}
+		seen[name] = k
+
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(dotenvEscaper.Replace(ScalarString(values[k])))
+		b.WriteString("\"\n")
+	}
+	return []byte(b.String())
+}
+
+var dotenvEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func dotenvName(key string) string {
+	var b strings.Builder
+	lastUnderscore := false
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			b.WriteByte(c - 'a' + 'A')
+			lastUnderscore = false
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			b.WriteByte(c)
+			lastUnderscore = false
+		case c == '-':
+		default:
+			// runs of separators such as "[0]." collapse into one underscore
+			if !lastUnderscore && b.Len() > 0 {
+				b.WriteByte('_')
+				lastUnderscore = true
+			}
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUnflatten(t *testing.T) {
+	flat := map[string]any{
+		"server.port":         8080,
+		"servers[0].host":     "a",
+		"servers[1].host":     "b",
+		"servers[1].ports[0]": 80,
+		"tags":                []any{"x", "y"},
+		"labels[team]":        "core",
+	}
+
+	got, err := Unflatten(flat)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]any{
+		"server": map[string]any{"port": 8080},
+		"servers": []any{
+			map[string]any{"host": "a"},
+			map[string]any{"host": "b", "ports": []any{80}},
+		},
+		"tags":   []any{"x", "y"},
+		"labels": map[string]any{"team": "core"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unflatten() = %v, want %v", got, want)
+	}
+
+	if _, err := Unflatten(map[string]any{"a": 1, "a.b": 2}); err == nil {
+		t.Error("expected conflict error for a and a.b")
+	}
+	if _, err := Unflatten(map[string]any{"a[99999999]": 1}); err == nil {
+		t.Error("expected error for huge list index")
+	}
+}
+
+func TestRender(t *testing.T) {
+	flat := map[string]any{
+		"server.port": json.Number("8080"),
+		"db.url":      "jdbc:postgresql://host/app",
+		"servers":     []any{map[string]any{"host": "a"}},
+		"greeting":    " héllo = world",
+		"max-size":    1.5,
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatYAML, "db:\n    url: jdbc:postgresql://host/app\ngreeting: ' héllo = world'\nmax-size: 1.5\nserver:\n    port: 8080\nservers:\n    - host: a\n"},
+		{FormatJSON, "{\n  \"db\": {\n    \"url\": \"jdbc:postgresql://host/app\"\n  },\n  \"greeting\": \" héllo = world\",\n  \"max-size\": 1.5,\n  \"server\": {\n    \"port\": 8080\n  },\n  \"servers\": [\n    {\n      \"host\": \"a\"\n    }\n  ]\n}\n"},
+		{FormatProperties, "db.url=jdbc:postgresql://host/app\ngreeting=\\ h\\u00E9llo = world\nmax-size=1.5\nserver.port=8080\nservers[0].host=a\n"},
+		{FormatDotenv, "DB_URL=\"jdbc:postgresql://host/app\"\nGREETING=\" héllo = world\"\nMAXSIZE=\"1.5\"\nSERVER_PORT=\"8080\"\nSERVERS_0_HOST=\"a\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got, err := Render(flat, tt.format)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Render(%s) =\n%s\nwant\n%s", tt.format, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Render(flat, "xml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestRender_PropertiesRoundTrip(t *testing.T) {
+	flat := map[string]any{
+		"key with spaces": "value",
+		"path":            `C:\temp`,
+		"multi":           "line1\nline2",
+		"#notcomment":     "!bang",
+		"emoji":           "😀",
+	}
+
+	data, err := Render(flat, FormatProperties)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := parseProperties(data, ParseOptions{StrictTypes: true})
+	if err != nil {
+		t.Fatalf("failed to parse rendered properties: %v", err)
+	}
+	if !reflect.DeepEqual(got, flat) {
+		t.Errorf("round trip = %v, want %v", got, flat)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect helper
+ * https://github.com/PakaiWA/PakaiWA/tree/main/internal/helper
+ */
+
+package helper
+
+import (
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+func unmarshalTOML(data []byte) (map[string]any, error) {
+	var m map[string]any
+	if err := toml.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return normalizeTOML(m).(map[string]any), nil
+}
+
+// normalizeTOML converts TOML datetime values into JSON friendly strings:
+// offset datetimes as RFC 3339, local dates, times and datetimes in their TOML form.
+func normalizeTOML(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, vv := range t {
+			t[k] = normalizeTOML(vv)
+		}
+		return t
+	case []any:
+		for i, vv := range t {
+			t[i] = normalizeTOML(vv)
+		}
+		return t
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+	case toml.LocalDate:
+		return t.String()
+	case toml.LocalTime:
+		return t.String()
+	case toml.LocalDateTime:
+		return t.String()
+	default:
+		return t
+	}
+}
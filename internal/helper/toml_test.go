@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package helper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFile_TOML(t *testing.T) {
+	data := []byte(`
+title = "orders"
+retries = 3
+ratio = 0.75
+enabled = true
+
+[server]
+host = "localhost"
+port = 8080
+
+[server.tls]
+ciphers = ["A", "B"]
+
+[[upstreams]]
+name = "a"
+weight = 1
+
+[[upstreams]]
+name = "b"
+
+[schedule]
+released = 2025-09-22T07:29:00+07:00
+day = 2025-09-22
+at = 07:29:00
+local = 2025-09-22T07:29:00
+`)
+
+	t.Run("Dot notation", func(t *testing.T) {
+		result, err := ParseFile(data, ".toml")
+		if err != nil {
+			t.Fatalf("ParseFile() unexpected error: %v", err)
+		}
+
+		expected := map[string]any{
+			"title":              "orders",
+			"retries":            int64(3),
+			"ratio":              0.75,
+			"enabled":            true,
+			"server.host":        "localhost",
+			"server.port":        int64(8080),
+			"server.tls.ciphers": []any{"A", "B"},
+			"upstreams": []any{
+				map[string]any{"name": "a", "weight": int64(1)},
+				map[string]any{"name": "b"},
+			},
+			"schedule.released": "2025-09-22T07:29:00+07:00",
+			"schedule.day":      "2025-09-22",
+			"schedule.at":       "07:29:00",
+			"schedule.local":    "2025-09-22T07:29:00",
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("ParseFile() = %#v, want %#v", result, expected)
+		}
+	})
+
+	t.Run("Indexed arrays of tables", func(t *testing.T) {
+		result, err := ParseFileWithOptions(data, ".toml", ParseOptions{IndexedArrays: true})
+		if err != nil {
+			t.Fatalf("ParseFileWithOptions() unexpected error: %v", err)
+		}
+		if result["upstreams[1].name"] != "b" || result["server.tls.ciphers[0]"] != "A" {
+			t.Errorf("expected indexed keys, got %#v", result)
+		}
+	})
+
+	t.Run("Invalid TOML", func(t *testing.T) {
+		if _, err := ParseFile([]byte("key = "), ".toml"); err == nil {
+			t.Error("expected error for invalid TOML")
+		}
+	})
+}
@@ -17,20 +17,35 @@ package repository
 import (
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/memory"
 )
 
 type GitRepo struct {
 	Path string
 	URL  string
+
+	// branchLocks serializes Pull and FetchHistory per branch, as both write to the
+	// branch's .git directory.
+	branchLocks sync.Map
+}
+
+// lockBranch locks branch for writing and returns the unlock function.
+func (g *GitRepo) lockBranch(branch string) func() {
+	mu, _ := g.branchLocks.LoadOrStore(branch, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
 }
 
 func NewGitRepo(path, url string) *GitRepo {
@@ -120,6 +135,8 @@ func (g *GitRepo) EnsureBranch(branch string) (string, error) {
 }
 
 func (g *GitRepo) Pull(branch string) error {
+	defer g.lockBranch(branch)()
+
 	branchPath := filepath.Join(g.Path, branch)
 
 	repo, err := git.PlainOpen(branchPath)
@@ -177,3 +194,306 @@ func (g *GitRepo) ListLocalBranches() ([]string, error) {
 	}
 	return branches, nil
 }
+
+// unshallowDepth asks the remote for the whole history, like git fetch --unshallow.
+const unshallowDepth = 1<<31 - 1
+
+// FetchHistory deepens the shallow clone of branch to the full history of the branch.
+// Branches are cloned with Depth 1 to keep startup fast, so history is only fetched
+// when a request needs it. It is a no-op for complete clones.
+func (g *GitRepo) FetchHistory(branch string) error {
+	defer g.lockBranch(branch)()
+
+	branchPath := filepath.Join(g.Path, branch)
+
+	repo, err := git.PlainOpen(branchPath)
+	if err != nil {
+		return fmt.Errorf("failed to open repo at %s: %w", branchPath, err)
+	}
+
+	shallows, err := repo.Storer.Shallow()
+	if err != nil {
+		return fmt.Errorf("failed to read shallow commits of branch %s: %w", branch, err)
+	}
+	if len(shallows) == 0 {
+		return nil
+	}
+
+	log.Printf("📜 Fetching history of branch %q...", branch)
+	refSpec := config.RefSpec(fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", branch, branch))
+	err = repo.Fetch(&git.FetchOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{refSpec},
+		Depth:      unshallowDepth,
+		Force:      true,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to fetch history of branch %s: %w", branch, err)
+	}
+
+	// go-git keeps the old shallow boundaries after deepening, so drop the ones whose
+	// parents are now present
+	var remaining []plumbing.Hash
+	for _, hash := range shallows {
+		if !hasParents(repo, hash) {
+			remaining = append(remaining, hash)
+		}
+	}
+	return repo.Storer.SetShallow(remaining)
+}
+
+func hasParents(repo *git.Repository, hash plumbing.Hash) bool {
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return false
+	}
+	for _, parent := range commit.ParentHashes {
+		if _, err := repo.CommitObject(parent); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// CommitAt returns the commit branch pointed to at time at: the first commit at or
+// before at on the first-parent history of HEAD, by committer time. Commits merged
+// in from other branches are skipped, as they were not on branch when made. It
+// returns ErrRevisionNotFound when the available history starts after at.
+func (g *GitRepo) CommitAt(branch string, at time.Time) (string, error) {
+	branchPath := filepath.Join(g.Path, branch)
+	notFound := fmt.Errorf("%w: %s on branch %s", ErrRevisionNotFound, at.Format(time.RFC3339), branch)
+
+	repo, err := git.PlainOpen(branchPath)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return "", notFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to open repo at %s: %w", branchPath, err)
+	}
+
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return "", notFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD for branch %s: %w", branch, err)
+	}
+
+	hash := head.Hash()
+	for {
+		commit, err := repo.CommitObject(hash)
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			// past the shallow boundary
+			return "", notFound
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read commit %s on branch %s: %w", hash, branch, err)
+		}
+
+		if !commit.Committer.When.After(at) {
+			return commit.Hash.String(), nil
+		}
+		if len(commit.ParentHashes) == 0 {
+			return "", notFound
+		}
+		hash = commit.ParentHashes[0]
+	}
+}
+
+// LastCommitForPaths returns the most recent commit on branch that touched any of the
+// given paths (relative to the branch root, slash separated). It returns "" when none
+// of the paths appear in the available history. On a shallow clone, paths unchanged
+// since the clone's oldest commit report that commit.
+func (g *GitRepo) LastCommitForPaths(branch string, paths []string) (string, error) {
+	return g.LastCommitForPathsAt(branch, "", paths)
+}
+
+// LastCommitForPathsAt is LastCommitForPaths walking back from commit from instead of
+// HEAD. An empty from means HEAD.
+func (g *GitRepo) LastCommitForPathsAt(branch, from string, paths []string) (string, error) {
+	if len(paths) == 0 {
+		return "", nil
+	}
+
+	branchPath := filepath.Join(g.Path, branch)
+
+	repo, err := git.PlainOpen(branchPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repo at %s: %w", branchPath, err)
+	}
+
+	wanted := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		wanted[filepath.ToSlash(p)] = struct{}{}
+	}
+
+	var start plumbing.Hash
+	if from != "" {
+		start = plumbing.NewHash(from)
+	}
+	iter, err := shallowLog(repo, start, func(p string) bool {
+		_, ok := wanted[p]
+		return ok
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read log for branch %s: %w", branch, err)
+	}
+	defer iter.Close()
+
+	commit, err := iter.Next()
+	if errors.Is(err, io.EOF) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to walk log for branch %s: %w", branch, err)
+	}
+
+	return commit.Hash.String(), nil
+}
+
+// shallowLog is repo.Log from commit from (HEAD when zero) keeping the commits that
+// change a path for which match returns true. Unlike repo.Log it stops at the shallow
+// boundary of a clone instead of failing on the missing parents: like git log, it
+// treats a shallow commit as the root of the history, adding every file it has.
+func shallowLog(repo *git.Repository, from plumbing.Hash, match func(path string) bool) (object.CommitIter, error) {
+	if from.IsZero() {
+		head, err := repo.Head()
+		if err != nil {
+			return nil, err
+		}
+		from = head.Hash()
+	}
+	start, err := repo.CommitObject(from)
+	if err != nil {
+		return nil, err
+	}
+
+	shallows, err := repo.Storer.Shallow()
+	if err != nil {
+		return nil, err
+	}
+	var missing []plumbing.Hash
+	for _, hash := range shallows {
+		commit, err := repo.CommitObject(hash)
+		if err != nil {
+			continue
+		}
+		for _, parent := range commit.ParentHashes {
+			if repo.Storer.HasEncodedObject(parent) != nil {
+				missing = append(missing, parent)
+			}
+		}
+	}
+
+	iter := object.NewCommitPreorderIter(start, nil, missing)
+	return object.NewCommitPathIterFromIter(match, iter, false), nil
+}
+
+// CommitInfo describes a commit in the history of a branch. Parent is the first
+// parent, or "" for a root commit.
+type CommitInfo struct {
+	Hash    string
+	Parent  string
+	Author  string
+	Email   string
+	When    time.Time
+	Message string
+}
+
+// WalkCommits calls fn for the commits on branch that change a path (relative to the
+// branch root, slash separated) for which match returns true, newest first. The walk
+// ends when fn returns false or at the start of the available history.
+func (g *GitRepo) WalkCommits(branch string, match func(path string) bool, fn func(CommitInfo) bool) error {
+	branchPath := filepath.Join(g.Path, branch)
+
+	repo, err := git.PlainOpen(branchPath)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return fmt.Errorf("%w: branch %s", ErrRevisionNotFound, branch)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open repo at %s: %w", branchPath, err)
+	}
+
+	iter, err := repo.Log(&git.LogOptions{Order: git.LogOrderCommitterTime, PathFilter: match})
+	if err != nil {
+		return fmt.Errorf("failed to read log for branch %s: %w", branch, err)
+	}
+	defer iter.Close()
+
+	for {
+		commit, err := iter.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to walk log for branch %s: %w", branch, err)
+		}
+
+		info := CommitInfo{
+			Hash:    commit.Hash.String(),
+			Author:  commit.Author.Name,
+			Email:   commit.Author.Email,
+			When:    commit.Author.When,
+			Message: strings.TrimSpace(commit.Message),
+		}
+		if len(commit.ParentHashes) > 0 {
+			info.Parent = commit.ParentHashes[0].String()
+		}
+		if !fn(info) {
+			return nil
+		}
+	}
+}
+
+// FileRevision is the content of a file as of one commit.
+type FileRevision struct {
+	Commit string
+	Data   []byte
+}
+
+// FileHistory returns the revisions of path (relative to the branch root, slash
+// separated) on branch, newest first, one per commit that changed it. The walk stops
+// where the file did not exist yet or at the start of the available history.
+func (g *GitRepo) FileHistory(branch, path string) ([]FileRevision, error) {
+	branchPath := filepath.Join(g.Path, branch)
+
+	repo, err := git.PlainOpen(branchPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repo at %s: %w", branchPath, err)
+	}
+
+	path = filepath.ToSlash(path)
+	iter, err := shallowLog(repo, plumbing.ZeroHash, func(p string) bool { return p == path })
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log for branch %s: %w", branch, err)
+	}
+	defer iter.Close()
+
+	var revisions []FileRevision
+	for {
+		commit, err := iter.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk log for branch %s: %w", branch, err)
+		}
+
+		file, err := commit.File(path)
+		if errors.Is(err, object.ErrFileNotFound) {
+			// deleted in this commit; older content belongs to a previous incarnation
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s at %s: %w", path, commit.Hash, err)
+		}
+
+		contents, err := file.Contents()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s at %s: %w", path, commit.Hash, err)
+		}
+		revisions = append(revisions, FileRevision{Commit: commit.Hash.String(), Data: []byte(contents)})
+	}
+
+	return revisions, nil
+}
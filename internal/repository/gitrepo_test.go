@@ -15,9 +15,12 @@
 package repository
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/go-git/go-git/v5"
@@ -229,6 +232,33 @@ func TestGitRepo_Pull(t *testing.T) {
 	}
 }
 
+func TestGitRepo_LockBranch(t *testing.T) {
+	repo := NewGitRepo(t.TempDir(), "")
+
+	unlock := repo.lockBranch("main")
+	done := make(chan struct{})
+	go func() {
+		_ = repo.Pull("main")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Pull ran while FetchHistory held the branch")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// other branches are not blocked
+	repo.lockBranch("develop")()
+
+	unlock()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Pull did not run after the branch was unlocked")
+	}
+}
+
 func TestGitRepo_EnsureBranch_Clone(t *testing.T) {
 	originDir := t.TempDir()
 	originGit, err := git.PlainInit(originDir, false)
@@ -276,3 +306,323 @@ func TestGitRepo_EnsureBranch_Clone(t *testing.T) {
 		t.Error("expected clone error for invalid remote URL")
 	}
 }
+
+func TestGitRepo_LastCommitForPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	fixture := newTestRepo(t, filepath.Join(tmpDir, "main"))
+
+	now := time.Now()
+	fixture.write("prod/orders-prod.yaml", "a: 1")
+	ordersCommit := fixture.commit("orders", now.Add(-2*time.Minute))
+	fixture.write("prod/billing-prod.yaml", "b: 1")
+	billingCommit := fixture.commit("billing", now.Add(-time.Minute))
+
+	repo := NewGitRepo(tmpDir, "")
+
+	got, err := repo.LastCommitForPaths("main", []string{"prod/orders-prod.yaml"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != ordersCommit.String() {
+		t.Errorf("expected orders commit %s, got %s", ordersCommit, got)
+	}
+
+	got, _ = repo.LastCommitForPaths("main", []string{"prod/orders-prod.yaml", "prod/billing-prod.yaml"})
+	if got != billingCommit.String() {
+		t.Errorf("expected billing commit %s, got %s", billingCommit, got)
+	}
+
+	got, err = repo.LastCommitForPaths("main", []string{"prod/unknown.yaml"})
+	if err != nil || got != "" {
+		t.Errorf("expected no commit for untracked path, got %q (err %v)", got, err)
+	}
+
+	if _, err := repo.LastCommitForPaths("nonexistent", []string{"a.yaml"}); err == nil {
+		t.Error("expected error for nonexistent branch")
+	}
+}
+
+func TestGitRepo_LastCommitForPaths_Shallow(t *testing.T) {
+	originDir := t.TempDir()
+	origin := newTestRepo(t, originDir)
+	now := time.Now()
+	origin.write("prod/orders-prod.yaml", "a: 1")
+	origin.commit("orders", now.Add(-2*time.Minute))
+	origin.write("prod/billing-prod.yaml", "b: 1")
+	head := origin.commit("billing", now.Add(-time.Minute))
+
+	// EnsureBranch clones with Depth 1, so the walk reaches the shallow boundary
+	repo := NewGitRepo(t.TempDir(), originDir)
+	if _, err := repo.EnsureBranch("main"); err != nil {
+		t.Fatalf("failed to clone: %v", err)
+	}
+
+	got, err := repo.LastCommitForPaths("main", []string{"prod/orders-prod.yaml"})
+	if err != nil || got != head.String() {
+		t.Errorf("expected the shallow commit %s, got %q (err %v)", head, got, err)
+	}
+	got, err = repo.LastCommitForPaths("main", []string{"prod/unknown.yaml"})
+	if err != nil || got != "" {
+		t.Errorf("expected no commit for untracked path, got %q (err %v)", got, err)
+	}
+}
+
+func TestGitRepo_FileHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	fixture := newTestRepo(t, filepath.Join(tmpDir, "main"))
+
+	now := time.Now()
+	fixture.write("prod/app.yaml", "a: 1")
+	first := fixture.commit("first", now.Add(-4*time.Minute))
+	fixture.write("prod/other.yaml", "b: 1")
+	fixture.commit("other", now.Add(-3*time.Minute))
+	fixture.write("prod/app.yaml", "a: 2")
+	second := fixture.commit("second", now.Add(-2*time.Minute))
+
+	repo := NewGitRepo(tmpDir, "")
+	revs, err := repo.FileHistory("main", "prod/app.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(revs) != 2 {
+		t.Fatalf("expected 2 revisions, got %d", len(revs))
+	}
+	if revs[0].Commit != second.String() || string(revs[0].Data) != "a: 2" {
+		t.Errorf("unexpected newest revision %+v", revs[0])
+	}
+	if revs[1].Commit != first.String() || string(revs[1].Data) != "a: 1" {
+		t.Errorf("unexpected oldest revision %+v", revs[1])
+	}
+
+	if revs, err := repo.FileHistory("main", "prod/missing.yaml"); err != nil || len(revs) != 0 {
+		t.Errorf("expected no revisions for untracked file, got %v (err %v)", revs, err)
+	}
+}
+
+func TestGitRepo_FileHistory_Shallow(t *testing.T) {
+	originDir := t.TempDir()
+	origin := newTestRepo(t, originDir)
+	now := time.Now()
+	origin.write("prod/app.yaml", "a: 1")
+	origin.commit("first", now.Add(-2*time.Minute))
+	origin.write("prod/app.yaml", "a: 2")
+	head := origin.commit("second", now.Add(-time.Minute))
+
+	repo := NewGitRepo(t.TempDir(), originDir)
+	if _, err := repo.EnsureBranch("main"); err != nil {
+		t.Fatalf("failed to clone: %v", err)
+	}
+
+	// the walk ends at the shallow boundary instead of failing
+	revs, err := repo.FileHistory("main", "prod/app.yaml")
+	if err != nil || len(revs) != 1 || revs[0].Commit != head.String() || string(revs[0].Data) != "a: 2" {
+		t.Errorf("expected only the shallow revision %s, got %+v (err %v)", head, revs, err)
+	}
+}
+
+func TestGitRepo_CommitTree(t *testing.T) {
+	tmpDir := t.TempDir()
+	fixture := newTestRepo(t, filepath.Join(tmpDir, "main"))
+
+	fixture.write("prod/app.yaml", "a: 1")
+	fixture.write("application.yaml", "b: 1")
+	first := fixture.commit("first", time.Now())
+	fixture.write("prod/app.yaml", "a: 2")
+	fixture.commit("second", time.Now())
+
+	repo := NewGitRepo(tmpDir, "")
+	fsys, hash, err := repo.CommitTree("main", first.String()[:8])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hash != first.String() {
+		t.Errorf("expected full hash %s, got %s", first, hash)
+	}
+
+	if err := fstest.TestFS(fsys, "prod/app.yaml", "application.yaml"); err != nil {
+		t.Errorf("tree is not a valid fs.FS: %v", err)
+	}
+
+	data, err := fs.ReadFile(fsys, "prod/app.yaml")
+	if err != nil || string(data) != "a: 1" {
+		t.Errorf("expected content of first commit, got %q (err %v)", data, err)
+	}
+	if _, err := fs.Stat(fsys, "prod/missing.yaml"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist, got %v", err)
+	}
+
+	if _, _, err := repo.CommitTree("main", "deadbeef"); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("expected ErrRevisionNotFound, got %v", err)
+	}
+}
+
+func TestGitRepo_CommitAt(t *testing.T) {
+	tmpDir := t.TempDir()
+	fixture := newTestRepo(t, filepath.Join(tmpDir, "main"))
+	commit := func(content string, when time.Time) plumbing.Hash {
+		fixture.write("app.yaml", content)
+		return fixture.commit(content, when)
+	}
+
+	base := time.Date(2025, 9, 1, 14, 0, 0, 0, time.UTC)
+	first := commit("a: 1", base)
+	second := commit("a: 2", base.Add(time.Hour))
+
+	repo := NewGitRepo(tmpDir, "")
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{base, first.String()},
+		{base.Add(30 * time.Minute), first.String()},
+		{base.Add(time.Hour), second.String()},
+		{base.Add(24 * time.Hour), second.String()},
+	}
+	for _, tt := range tests {
+		got, err := repo.CommitAt("main", tt.at)
+		if err != nil || got != tt.want {
+			t.Errorf("CommitAt(%s) = %s (err %v), want %s", tt.at, got, err, tt.want)
+		}
+	}
+
+	if _, err := repo.CommitAt("main", base.Add(-time.Second)); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("expected ErrRevisionNotFound before the first commit, got %v", err)
+	}
+	if _, err := repo.CommitAt("missing", base); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("expected ErrRevisionNotFound for a missing branch, got %v", err)
+	}
+
+	// complete clones need no fetch, so no remote is required
+	if err := repo.FetchHistory("main"); err != nil {
+		t.Errorf("expected FetchHistory to be a no-op, got %v", err)
+	}
+}
+
+func TestGitRepo_CommitAt_Merge(t *testing.T) {
+	tmpDir := t.TempDir()
+	fixture := newTestRepo(t, filepath.Join(tmpDir, "main"))
+	commit := func(content string, when time.Time, parents ...plumbing.Hash) plumbing.Hash {
+		fixture.write("app.yaml", content)
+		return fixture.commit(content, when, parents...)
+	}
+	checkout := func(branch string, create bool) {
+		err := fixture.wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(branch), Create: create})
+		if err != nil {
+			t.Fatalf("failed to check out %s: %v", branch, err)
+		}
+	}
+
+	// a feature branch made at 14:10 and merged at 15:00
+	base := time.Date(2025, 9, 1, 14, 0, 0, 0, time.UTC)
+	first := commit("a: 1", base)
+	checkout("feature", true)
+	feature := commit("a: feature", base.Add(10*time.Minute))
+	checkout("main", false)
+	merge := commit("a: merged", base.Add(time.Hour), first, feature)
+
+	repo := NewGitRepo(tmpDir, "")
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{base.Add(15 * time.Minute), first.String()},
+		{base.Add(time.Hour), merge.String()},
+	}
+	for _, tt := range tests {
+		got, err := repo.CommitAt("main", tt.at)
+		if err != nil || got != tt.want {
+			t.Errorf("CommitAt(%s) = %s (err %v), want %s", tt.at, got, err, tt.want)
+		}
+	}
+}
+
+func TestGitRepo_WalkCommits(t *testing.T) {
+	tmpDir := t.TempDir()
+	fixture := newTestRepo(t, filepath.Join(tmpDir, "main"))
+	commit := func(name, msg string, when time.Time) plumbing.Hash {
+		fixture.write(name, msg)
+		return fixture.commit(msg+"\n", when)
+	}
+
+	now := time.Now()
+	first := commit("app.yaml", "first", now.Add(-3*time.Minute))
+	commit("other.yaml", "other", now.Add(-2*time.Minute))
+	third := commit("app.yaml", "third", now.Add(-time.Minute))
+
+	repo := NewGitRepo(tmpDir, "")
+	match := func(p string) bool { return p == "app.yaml" }
+
+	var seen []CommitInfo
+	err := repo.WalkCommits("main", match, func(c CommitInfo) bool {
+		seen = append(seen, c)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 2 || seen[0].Hash != third.String() || seen[1].Hash != first.String() {
+		t.Fatalf("unexpected commits %+v", seen)
+	}
+	if seen[0].Message != "third" || seen[0].Author != "Test" || seen[0].Parent == "" || seen[1].Parent != "" {
+		t.Errorf("unexpected commit details %+v", seen)
+	}
+
+	count := 0
+	_ = repo.WalkCommits("main", match, func(CommitInfo) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("expected the walk to stop after the first commit, got %d", count)
+	}
+
+	if err := repo.WalkCommits("missing", match, func(CommitInfo) bool { return true }); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("expected ErrRevisionNotFound, got %v", err)
+	}
+}
+
+// testRepo is a git repository for tests, on branch main.
+type testRepo struct {
+	t   *testing.T
+	dir string
+	wt  *git.Worktree
+}
+
+func newTestRepo(t *testing.T, dir string) *testRepo {
+	t.Helper()
+	repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.Main},
+	})
+	if err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	return &testRepo{t: t, dir: dir, wt: wt}
+}
+
+// write stages name, relative to the repository root, with content.
+func (r *testRepo) write(name, content string) {
+	r.t.Helper()
+	full := filepath.Join(r.dir, filepath.FromSlash(name))
+	_ = os.MkdirAll(filepath.Dir(full), 0755)
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		r.t.Fatalf("failed to write %s: %v", name, err)
+	}
+	_, _ = r.wt.Add(name)
+}
+
+// commit commits the staged files at when, on top of parents if given, else HEAD.
+func (r *testRepo) commit(msg string, when time.Time, parents ...plumbing.Hash) plumbing.Hash {
+	r.t.Helper()
+	hash, err := r.wt.Commit(msg, &git.CommitOptions{
+		Author:  &object.Signature{Name: "Test", Email: "test@example.com", When: when},
+		Parents: parents,
+	})
+	if err != nil {
+		r.t.Fatalf("failed to commit: %v", err)
+	}
+	return hash
+}
@@ -0,0 +1,227 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect repository
+ */
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ErrRevisionNotFound is returned when a commit cannot be resolved in a branch clone.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// CommitTree resolves rev (a full or abbreviated commit hash, or another revision
+// go-git understands such as HEAD~2) in the clone of branch and returns the file tree
+// of that commit as a read-only fs.FS, with the full commit hash. Only directories
+// and regular files are visible; symlinks and submodules are left out.
+func (g *GitRepo) CommitTree(branch, rev string) (fs.FS, string, error) {
+	branchPath := filepath.Join(g.Path, branch)
+
+	repo, err := git.PlainOpen(branchPath)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return nil, "", fmt.Errorf("%w: %s on branch %s", ErrRevisionNotFound, rev, branch)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open repo at %s: %w", branchPath, err)
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %s on branch %s", ErrRevisionNotFound, rev, branch)
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %s on branch %s", ErrRevisionNotFound, rev, branch)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read tree of %s: %w", commit.Hash, err)
+	}
+
+	return &treeFS{tree: tree}, commit.Hash.String(), nil
+}
+
+// treeFS exposes a git tree as an fs.FS.
+type treeFS struct {
+	tree *object.Tree
+}
+
+func (t *treeFS) Open(name string) (fs.File, error) {
+	info, err := t.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		entries, err := t.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		return &treeDir{info: info, entries: entries}, nil
+	}
+
+	file, err := t.tree.File(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	reader, err := file.Reader()
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &treeFile{info: info, ReadCloser: reader}, nil
+}
+
+func (t *treeFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return treeInfo{name: ".", mode: fs.ModeDir | 0o555}, nil
+	}
+
+	entry, err := t.tree.FindEntry(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	info, ok := t.entryInfo(entry)
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return info, nil
+}
+
+func (t *treeFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	dir := t.tree
+	if name != "." {
+		sub, err := t.tree.Tree(name)
+		if err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+		}
+		dir = sub
+	}
+
+	sub := &treeFS{tree: dir}
+	var entries []fs.DirEntry
+	for i := range dir.Entries {
+		if info, ok := sub.entryInfo(&dir.Entries[i]); ok {
+			entries = append(entries, fs.FileInfoToDirEntry(info))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+func (t *treeFS) ReadFile(name string) ([]byte, error) {
+	info, err := t.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: errors.New("is a directory")}
+	}
+
+	file, err := t.tree.File(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	contents, err := file.Contents()
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+	return []byte(contents), nil
+}
+
+// entryInfo describes a tree entry, reporting false for entries that are neither
+// directories nor regular files.
+func (t *treeFS) entryInfo(entry *object.TreeEntry) (fs.FileInfo, bool) {
+	switch entry.Mode {
+	case filemode.Dir:
+		return treeInfo{name: path.Base(entry.Name), mode: fs.ModeDir | 0o555}, true
+	case filemode.Regular, filemode.Deprecated, filemode.Executable:
+		var size int64
+		if file, err := t.tree.TreeEntryFile(entry); err == nil {
+			size = file.Size
+		}
+		return treeInfo{name: path.Base(entry.Name), size: size, mode: 0o444}, true
+	default:
+		return nil, false
+	}
+}
+
+type treeInfo struct {
+	name string
+	size int64
+	mode fs.FileMode
+}
+
+func (i treeInfo) Name() string       { return i.name }
+func (i treeInfo) Size() int64        { return i.size }
+func (i treeInfo) Mode() fs.FileMode  { return i.mode }
+func (i treeInfo) ModTime() time.Time { return time.Time{} }
+func (i treeInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i treeInfo) Sys() any           { return nil }
+
+type treeFile struct {
+	info fs.FileInfo
+	io.ReadCloser
+}
+
+func (f *treeFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+type treeDir struct {
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *treeDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *treeDir) Close() error               { return nil }
+
+func (d *treeDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.Name(), Err: errors.New("is a directory")}
+}
+
+func (d *treeDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+	return rest[:n], nil
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect service
+ * https://github.com/KAnggara75/conflect/tree/main/internal/service
+ */
+
+package service
+
+import (
+	"io/fs"
+	"path"
+
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/helper"
+)
+
+// Batch loads several configs with the same options, reading and parsing each file
+// once even when several applications share it. A Batch is not safe for concurrent
+// use.
+type Batch struct {
+	c    *ConfigService
+	opts LoadOptions
+}
+
+// NewBatch starts a batch of config loads.
+func (c *ConfigService) NewBatch(opts LoadOptions) *Batch {
+	opts.files = &fileCache{files: make(map[fileKey]cachedFile)}
+	return &Batch{c: c, opts: opts}
+}
+
+// Load is LoadConfigWithOptions within the batch.
+func (b *Batch) Load(appName, env, label string) *dto.ConfigResponse {
+	return b.c.load(appName, env, label, b.opts).response
+}
+
+// fileCache keeps parsed config files by tree and path. Parsed documents are shared
+// between responses and must not be modified.
+type fileCache struct {
+	files map[fileKey]cachedFile
+}
+
+type fileKey struct {
+	tree string
+	path string
+	opts helper.ParseOptions
+}
+
+type cachedFile struct {
+	docs []helper.Document
+	err  error
+}
+
+// parse reads and parses the file at name in fsys, identified across loads by tree.
+// A nil cache parses every time.
+func (fc *fileCache) parse(fsys fs.FS, tree, name string, opts helper.ParseOptions) ([]helper.Document, error) {
+	key := fileKey{tree: tree, path: name, opts: opts}
+	if fc != nil {
+		if cached, ok := fc.files[key]; ok {
+			return cached.docs, cached.err
+		}
+	}
+
+	var docs []helper.Document
+	data, err := fs.ReadFile(fsys, name)
+	if err == nil {
+		docs, err = helper.ParseDocuments(data, path.Ext(name), opts)
+	}
+
+	if fc != nil {
+		fc.files[key] = cachedFile{docs: docs, err: err}
+	}
+	return docs, err
+}
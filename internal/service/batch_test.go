@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/repository"
+)
+
+func TestConfigService_Batch(t *testing.T) {
+	tmpDir := t.TempDir()
+	envDir := filepath.Join(tmpDir, "main", "prod")
+	_ = os.MkdirAll(envDir, 0755)
+	_ = os.WriteFile(filepath.Join(envDir, "orders-prod.yaml"), []byte("name: orders\n"), 0644)
+	_ = os.WriteFile(filepath.Join(envDir, "billing-prod.yaml"), []byte("name: billing\n"), 0644)
+	_ = os.WriteFile(filepath.Join(envDir, "application.yaml"), []byte("region: eu\n"), 0644)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+
+	shared := func(resp *dto.ConfigResponse) map[string]any {
+		t.Helper()
+		if len(resp.PropertySources) != 2 || resp.PropertySources[1].Name != "application.yaml" {
+			t.Fatalf("unexpected property sources %+v", resp.PropertySources)
+		}
+		return resp.PropertySources[1].Source
+	}
+
+	batch := cs.NewBatch(LoadOptions{})
+	orders := batch.Load("orders", "prod", "main")
+	billing := batch.Load("billing", "prod", "")
+	if orders.PropertySources[0].Source["name"] != "orders" || billing.PropertySources[0].Source["name"] != "billing" {
+		t.Errorf("unexpected app sources %+v / %+v", orders.PropertySources, billing.PropertySources)
+	}
+	if reflect.ValueOf(shared(orders)).Pointer() != reflect.ValueOf(shared(billing)).Pointer() {
+		t.Error("expected application.yaml to be parsed once within the batch")
+	}
+	if got := len(batch.opts.files.files); got != 3 {
+		t.Errorf("expected 3 parsed files, got %d", got)
+	}
+
+	if !reflect.DeepEqual(orders, cs.LoadConfig("orders", "prod", "main")) {
+		t.Error("expected batch load to match a single load")
+	}
+	if reflect.ValueOf(shared(cs.LoadConfig("orders", "prod", "main"))).Pointer() == reflect.ValueOf(shared(orders)).Pointer() {
+		t.Error("expected loads outside the batch to parse again")
+	}
+}
@@ -16,11 +16,18 @@
 package service
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/KAnggara75/conflect/internal/config"
 	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
@@ -30,8 +37,10 @@ import (
 )
 
 type ConfigService struct {
-	repo *repository.GitRepo
-	cfg  *config.Config
+	repo        *repository.GitRepo
+	cfg         *config.Config
+	updates     branchUpdates
+	lastCommits lastCommitCache
 }
 
 func NewConfigService(cfg *config.Config) *ConfigService {
@@ -49,7 +58,11 @@ func NewConfigServiceFromRepo(repo *repository.GitRepo, cfg *config.Config) *Con
 
 func (c *ConfigService) UpdateRepo(branch string) error {
 	log.Printf("Pulling latest config for branch %s...", branch)
-	return c.repo.Pull(branch)
+	if err := c.repo.Pull(branch); err != nil {
+		return err
+	}
+	c.updates.notify(branch)
+	return nil
 }
 
 func (c *ConfigService) GetBranchSHA(branch string) (string, error) {
@@ -60,18 +73,83 @@ func (c *ConfigService) ListBranches() ([]string, error) {
 	return c.repo.ListLocalBranches()
 }
 
+// LoadOptions overrides server-wide settings for a single config request.
+// Zero values fall back to the server configuration.
+type LoadOptions struct {
+	ArrayFlatten string
+
+	// files shares parsed files between the loads of a Batch
+	files *fileCache
+	// skipLastCommit leaves LastCommit empty, sparing a log walk per load
+	skipLastCommit bool
+	// skipFetch reads a revision from the history already fetched, without
+	// deepening a shallow clone
+	skipFetch bool
+}
+
 func (c *ConfigService) LoadConfig(appName, env, label string) *dto.ConfigResponse {
+	return c.LoadConfigWithOptions(appName, env, label, LoadOptions{})
+}
+
+// LoadConfigWithOptions resolves the config of appName for env, which may list several
+// comma-separated profiles (e.g. "prod,mysql,eu-west"). Later profiles win.
+func (c *ConfigService) LoadConfigWithOptions(appName, env, label string, opts LoadOptions) *dto.ConfigResponse {
+	return c.load(appName, env, label, opts).response
+}
+
+// Revision pins a config load to a commit of the label (a full or abbreviated hash),
+// or to the last commit made at or before At. The zero Revision is the working tree.
+type Revision struct {
+	Commit string
+	At     time.Time
+}
+
+// IsZero reports whether r selects the working tree.
+func (r Revision) IsZero() bool {
+	return r.Commit == "" && r.At.IsZero()
+}
+
+// LoadConfigAt is LoadConfigWithOptions for the label as of rev. It fails with
+// errors.ErrNotFound when rev cannot be resolved on the label.
+func (c *ConfigService) LoadConfigAt(appName, env, label string, rev Revision, opts LoadOptions) (*dto.ConfigResponse, error) {
+	loaded := c.loadAt(appName, env, label, rev, opts)
+	return loaded.response, loaded.err
+}
+
+// loadedConfig is a config response together with the file each property source
+// was read from. err is set when the requested commit cannot be read.
+type loadedConfig struct {
+	response  *dto.ConfigResponse
+	origins   []string
+	parseOpts helper.ParseOptions
+	err       error
+}
+
+func (c *ConfigService) load(appName, env, label string, opts LoadOptions) *loadedConfig {
+	return c.loadAt(appName, env, label, Revision{}, opts)
+}
+
+// loadAt is load for label as of rev.
+func (c *ConfigService) loadAt(appName, env, label string, rev Revision, opts LoadOptions) *loadedConfig {
+	profiles := splitProfiles(env)
 
 	response := &dto.ConfigResponse{
 		Name:            appName,
-		Profiles:        []string{env},
+		Profiles:        profiles,
 		PropertySources: []dto.PropertySource{}, // inisialisasi slice kosong
 	}
+	loaded := &loadedConfig{response: response, parseOpts: c.parseOptions(opts)}
 
 	// validate inputs used as path components to avoid directory traversal
-	if !isSafePathComponent(appName) || !isSafePathComponent(env) {
+	if !isSafePathComponent(appName) || len(profiles) == 0 {
 		log.Printf("invalid appName or env: appName=%q, env=%q", appName, env)
-		return response
+		return loaded
+	}
+	for _, profile := range profiles {
+		if !isSafePathComponent(profile) {
+			log.Printf("invalid appName or env: appName=%q, env=%q", appName, env)
+			return loaded
+		}
 	}
 
 	if label == "" {
@@ -80,30 +158,141 @@ func (c *ConfigService) LoadConfig(appName, env, label string) *dto.ConfigRespon
 
 	if !isSafePathComponent(label) {
 		log.Printf("invalid label: %q", label)
-		return response
+		return loaded
 	}
 
 	response.Label = label
 
-	candidates, err := c.generateConfigCandidates(appName, env, label)
+	fsys, commit, err := c.configTree(label, rev, opts.skipFetch)
+	if err != nil {
+		log.Println(err)
+		loaded.err = err
+		return loaded
+	}
+
+	candidates, err := c.generateConfigCandidates(fsys, appName, profiles, label)
 	if err != nil {
 		log.Println(err)
-		return response
+		return loaded
 	}
 
-	data, err := c.findAndReadAllConfigs(label, env, candidates)
+	data, origins, err := c.findAndReadAllConfigs(fsys, candidates, profiles, loaded.parseOpts, opts.files, label+"@"+commit)
 	if err != nil {
 		log.Println(err)
-		return response
+		return loaded
+	}
+
+	switch c.cfg.Placeholders {
+	case config.PlaceholdersLeave, config.PlaceholdersFail:
+		data, err = resolvePlaceholders(data, c.cfg.Placeholders == config.PlaceholdersFail)
+		if err != nil {
+			log.Println(err)
+			response.Error = err.Error()
+			return loaded
+		}
 	}
 	response.PropertySources = data
+	loaded.origins = origins
+
+	// pin the working tree to HEAD so the lastCommit walk can be cached
+	if commit == "" {
+		commit, _ = c.repo.GetCommitHashFromBranch(label)
+	}
+	if commit != "" && !opts.skipLastCommit {
+		if last, err := c.lastCommit(label, commit, uniqueStrings(origins)); err == nil {
+			response.LastCommit = last
+		}
+	}
+
+	if c.cfg.VersionMode == config.VersionModeContent {
+		response.Version = contentVersion(data)
+	} else {
+		response.Version = commit
+	}
+
+	return loaded
+}
+
+// configTree returns the files of label as of rev, with the full commit hash, or the
+// working tree of label when rev is zero. Pinned revisions fetch the history of
+// shallow clones first, unless skipFetch is set.
+func (c *ConfigService) configTree(label string, rev Revision, skipFetch bool) (fs.FS, string, error) {
+	if rev.IsZero() {
+		return os.DirFS(filepath.Join(c.repo.Path, label)), "", nil
+	}
+
+	if !skipFetch {
+		if err := c.repo.FetchHistory(label); err != nil {
+			// the revision may still be in the history we have
+			log.Println(err)
+		}
+	}
+
+	commit := rev.Commit
+	if commit == "" {
+		var err error
+		commit, err = c.repo.CommitAt(label, rev.At)
+		if err != nil {
+			return nil, "", revisionError(err)
+		}
+	}
+
+	fsys, commit, err := c.repo.CommitTree(label, commit)
+	if err != nil {
+		return nil, "", revisionError(err)
+	}
+	return fsys, commit, nil
+}
 
-	hash, err := c.repo.GetCommitHashFromBranch(label)
-	if err == nil {
-		response.Version = hash
+// revisionError maps repository.ErrRevisionNotFound to errors.ErrNotFound.
+func revisionError(err error) error {
+	if stderrors.Is(err, repository.ErrRevisionNotFound) {
+		return fmt.Errorf("%w: %v", errors.ErrNotFound, err)
 	}
+	return err
+}
 
-	return response
+// uniqueStrings returns items without duplicates, keeping the first occurrence.
+func uniqueStrings(items []string) []string {
+	var (
+		out  []string
+		seen = make(map[string]bool, len(items))
+	)
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// splitProfiles splits a comma-separated env segment into its ordered profiles.
+func splitProfiles(env string) []string {
+	var profiles []string
+	for _, p := range strings.Split(env, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			profiles = append(profiles, p)
+		}
+	}
+	return profiles
+}
+
+// contentVersion hashes the resolved property sources so the version only changes
+// when the config served for this app/env changes.
+func contentVersion(sources []dto.PropertySource) string {
+	if len(sources) == 0 {
+		return ""
+	}
+
+	// json.Marshal sorts map keys, which keeps the hash deterministic
+	data, err := json.Marshal(sources)
+	if err != nil {
+		log.Printf("failed to hash property sources: %v", err)
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
 }
 
 // isSafePathComponent checks that s can safely be used as a single path component.
@@ -121,90 +310,204 @@ func isSafePathComponent(s string) bool {
 	return true
 }
 
-func (c *ConfigService) generateConfigCandidates(appName, env, label string) ([]string, error) {
-	basePath := filepath.Join(c.repo.Path, label, env)
+// configExtensions lists the supported config file extensions, highest priority
+// first. When a stem exists with several extensions, all are loaded in this order.
+var configExtensions = []string{".properties", ".yaml", ".yml", ".json", ".toml", ".hcl", ".env"}
+
+// generateConfigCandidates lists the config files for appName, highest priority
+// first and as paths relative to the label root. Like Spring Cloud Config,
+// profile-specific files come before the defaults and later profiles before earlier
+// ones: {app}-{profile}.* then application-{profile}.* in each search directory of
+// each profile in reverse, then application.* of those directories, and finally
+// {app}.* then application.* of each shared directory (SharedPaths in order, then
+// the branch root). File stems must match exactly, so pay-production.yaml is not a
+// candidate for pay/prod.
+func (c *ConfigService) generateConfigCandidates(fsys fs.FS, appName string, profiles []string, label string) ([]string, error) {
+	var (
+		profileFiles []string
+		globalFiles  []string
+		sharedFiles  []string
+		found        bool
+		seen         = make(map[string]bool)
+	)
 
-	entries, err := os.ReadDir(basePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read dir %s: %w", basePath, err)
+	// add appends the paths not yet listed, so overlapping search paths load a file once
+	add := func(list *[]string, paths []string) {
+		for _, p := range paths {
+			if !seen[p] {
+				seen[p] = true
+				*list = append(*list, p)
+			}
+		}
 	}
 
-	var (
-		appFiles         []string
-		applicationFiles []string
-		globalFiles      []string
-	)
+	for i := len(profiles) - 1; i >= 0; i-- {
+		profile := profiles[i]
 
-	for _, e := range entries {
-		if e.IsDir() {
-			continue
+		dirs, err := c.searchDirs(fsys, label, c.searchPaths(), appName, profile)
+		if err != nil {
+			return nil, err
 		}
-		name := e.Name()
-		ext := filepath.Ext(name)
 
-		switch ext {
-		case ".yaml", ".yml", ".json", ".properties":
-		default:
-			continue
+		stems := []string{appName + "-" + profile, "application-" + profile}
+		if appName == "application" {
+			stems = stems[1:]
+		}
+
+		profileFound := false
+		for _, dir := range dirs {
+			files, err := listConfigFiles(fsys, dir)
+			if err != nil {
+				return nil, err
+			}
+			if files == nil {
+				continue
+			}
+			profileFound = true
+
+			for _, stem := range stems {
+				add(&profileFiles, matchStem(files, dir, stem))
+			}
+			add(&globalFiles, matchStem(files, dir, "application"))
 		}
 
-		// {appName}-{env}.*
-		if strings.HasPrefix(name, appName+"-"+env) {
-			appFiles = append(appFiles, name)
+		if !profileFound {
+			log.Printf("skip profile %s: no search path found on %s", profile, label)
 			continue
 		}
+		found = true
+	}
 
-		// application-{env}.*
-		if strings.HasPrefix(name, "application-"+env) {
-			applicationFiles = append(applicationFiles, name)
+	sharedDirs, err := c.searchDirs(fsys, label, append(append([]string{}, c.cfg.SharedPaths...), ""), appName, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range sharedDirs {
+		files, err := listConfigFiles(fsys, dir)
+		if err != nil {
+			return nil, err
+		}
+		if files == nil {
 			continue
 		}
 
-		// application.*
-		if strings.HasPrefix(name, "application.") {
-			globalFiles = append(globalFiles, name)
+		if appName != "application" {
+			add(&sharedFiles, matchStem(files, dir, appName))
 		}
+		add(&sharedFiles, matchStem(files, dir, "application"))
 	}
 
-	var candidates []string
-	candidates = append(candidates, appFiles...)
-	candidates = append(candidates, applicationFiles...)
-	candidates = append(candidates, globalFiles...)
+	// shared files alone do not make an unknown env a config
+	if !found {
+		return nil, fmt.Errorf("failed to read dir for profiles %v on %s", profiles, label)
+	}
+
+	candidates := append(profileFiles, globalFiles...)
+	candidates = append(candidates, sharedFiles...)
 
 	log.Printf("Read File candidates: %v", candidates)
 
 	return candidates, nil
 }
 
-func (c *ConfigService) findAndReadAllConfigs(label, env string, candidates []string) ([]dto.PropertySource, error) {
-	var sources []dto.PropertySource
+// listConfigFiles returns the set of regular file names in dir, relative to the tree
+// root, or nil if the directory does not exist.
+func listConfigFiles(fsys fs.FS, dir string) (map[string]bool, error) {
+	if dir == "" {
+		dir = "."
+	}
 
-	for _, candidate := range candidates {
-		filePath := filepath.Join(c.repo.Path, label, env, candidate)
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		if stderrors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read dir %s: %w", dir, err)
+	}
+
+	files := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			files[e.Name()] = true
+		}
+	}
+	return files, nil
+}
+
+// matchStem returns dir/stem.ext for every supported extension present in files,
+// in configExtensions order.
+func matchStem(files map[string]bool, dir, stem string) []string {
+	var matches []string
+	for _, ext := range configExtensions {
+		if files[stem+ext] {
+			matches = append(matches, path.Join(dir, stem+ext))
+		}
+	}
+	return matches
+}
+
+// sourceName names the property source read from candidate: its file name, as in
+// earlier releases, or with SOURCE_NAMES=path its path relative to the label root,
+// which tells apart same-named files of different directories.
+func (c *ConfigService) sourceName(candidate string) string {
+	if c.cfg.SourceNames == config.SourceNamesPath {
+		return candidate
+	}
+	return path.Base(candidate)
+}
 
-		data, err := os.ReadFile(filePath)
+func (c *ConfigService) parseOptions(opts LoadOptions) helper.ParseOptions {
+	flatten := c.cfg.ArrayFlatten
+	if opts.ArrayFlatten != "" {
+		flatten = opts.ArrayFlatten
+	}
+	return helper.ParseOptions{
+		StrictTypes:   c.cfg.StrictTypes,
+		IndexedArrays: flatten == config.ArrayFlattenIndexed,
+	}
+}
+
+// findAndReadAllConfigs parses the candidates in priority order. Documents whose
+// profile activation does not match the requested profiles are dropped; the active
+// documents of a file become separate sources, later documents first. It also
+// returns the file each source was read from. Files are parsed through cache, keyed
+// by tree, when it is not nil.
+func (c *ConfigService) findAndReadAllConfigs(fsys fs.FS, candidates []string, profiles []string, parseOpts helper.ParseOptions, cache *fileCache, tree string) ([]dto.PropertySource, []string, error) {
+	var (
+		sources []dto.PropertySource
+		origins []string
+	)
+
+	for _, candidate := range candidates {
+		docs, err := cache.parse(fsys, tree, candidate, parseOpts)
 		if err != nil {
 			if skip, fileErr := errors.ShouldSkipFile(candidate, err); skip {
 				continue
 			} else {
-				return nil, fileErr
+				return nil, nil, fileErr
 			}
 		}
 
-		ext := filepath.Ext(filePath)
-		props, err := helper.ParseFile(data, ext)
-		if err != nil {
-			if skip, fileErr := errors.ShouldSkipFile(candidate, err); skip {
+		for i := len(docs) - 1; i >= 0; i-- {
+			active, err := helper.MatchesProfiles(docs[i].Profiles, profiles)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to process file %s: %w", candidate, err)
+			}
+			if !active {
 				continue
-			} else {
-				return nil, fileErr
 			}
+
+			name := c.sourceName(candidate)
+			if len(docs) > 1 {
+				name = fmt.Sprintf("%s (document #%d)", candidate, i)
+			}
+			sources = append(sources, dto.PropertySource{
+				Name:   name,
+				Source: docs[i].Source,
+			})
+			origins = append(origins, candidate)
 		}
-		sources = append(sources, dto.PropertySource{
-			Name:   candidate,
-			Source: props,
-		})
 	}
 
-	return sources, nil
+	return sources, origins, nil
 }
@@ -15,12 +15,15 @@
 package service
 
 import (
+	stderrors "errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/errors"
 	"github.com/KAnggara75/conflect/internal/repository"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -262,3 +265,471 @@ func TestConfigService_ListBranchesAndSHA(t *testing.T) {
 		t.Errorf("expected error updating nonexistent branch repo")
 	}
 }
+
+func TestConfigService_LoadConfig_ContentVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := newTestRepo(t, filepath.Join(tmpDir, "main"))
+	commitFile := func(name, content string) plumbing.Hash {
+		return repo.commit("update "+name, time.Now(), map[string]string{"prod/" + name: content})
+	}
+
+	ordersCommit := commitFile("orders-prod.yaml", "orders:\n  enabled: true\n")
+	commitFile("billing-prod.yaml", "billing:\n  currency: IDR\n")
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main", VersionMode: config.VersionModeContent}
+	cs := NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+
+	before := cs.LoadConfig("orders", "prod", "main")
+	if before.Version == "" {
+		t.Fatal("expected content version to be set")
+	}
+	if before.LastCommit != ordersCommit.String() {
+		t.Errorf("expected LastCommit %s, got %s", ordersCommit, before.LastCommit)
+	}
+
+	commitFile("billing-prod.yaml", "billing:\n  currency: USD\n")
+
+	after := cs.LoadConfig("orders", "prod", "main")
+	if after.Version != before.Version {
+		t.Errorf("expected orders version to stay %s after unrelated commit, got %s", before.Version, after.Version)
+	}
+
+	updateCommit := commitFile("orders-prod.yaml", "orders:\n  enabled: false\n")
+
+	changed := cs.LoadConfig("orders", "prod", "main")
+	if changed.Version == before.Version {
+		t.Error("expected orders version to change after its file changed")
+	}
+	if changed.LastCommit != updateCommit.String() {
+		t.Errorf("expected LastCommit %s after HEAD moved, got %s", updateCommit, changed.LastCommit)
+	}
+
+	// the lastCommit walk is cached per HEAD and file set
+	cached := len(cs.lastCommits.entries)
+	if again := cs.LoadConfig("orders", "prod", "main"); again.LastCommit != updateCommit.String() || len(cs.lastCommits.entries) != cached {
+		t.Errorf("expected a cached LastCommit %s, got %s with %d entries (was %d)", updateCommit, again.LastCommit, len(cs.lastCommits.entries), cached)
+	}
+
+	cfg.VersionMode = config.VersionModeCommit
+	head, _ := cs.GetBranchSHA("main")
+	if got := cs.LoadConfig("orders", "prod", "main").Version; got != head {
+		t.Errorf("expected commit version %s, got %s", head, got)
+	}
+}
+
+func TestConfigService_LoadConfig_ShallowLastCommit(t *testing.T) {
+	originDir := t.TempDir()
+	origin := newTestRepo(t, originDir)
+	origin.commit("add orders", time.Now().Add(-time.Minute), map[string]string{"prod/orders-prod.yaml": "timeout: 5\n"})
+	head := origin.commit("add billing", time.Now(), map[string]string{"prod/billing-prod.yaml": "currency: IDR\n"})
+
+	localDir := t.TempDir()
+	cloneTestRepo(t, originDir, filepath.Join(localDir, "main"), 1)
+
+	cfg := &config.Config{RepoPath: localDir, DefaultBranch: "main"}
+	cs := NewConfigServiceFromRepo(repository.NewGitRepo(localDir, originDir), cfg)
+
+	// the history stops at the clone depth, so the shallow commit is the last one
+	for range 2 {
+		if got := cs.LoadConfig("orders", "prod", "main").LastCommit; got != head.String() {
+			t.Errorf("expected LastCommit %s on a shallow clone, got %q", head, got)
+		}
+	}
+	if len(cs.lastCommits.entries) != 1 {
+		t.Errorf("expected the walk to be cached once, got %d entries", len(cs.lastCommits.entries))
+	}
+}
+
+func TestConfigService_LoadConfigAt(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := newTestRepo(t, filepath.Join(tmpDir, "main"))
+	commitFile := func(content string, when time.Time) plumbing.Hash {
+		return repo.commit("update orders", when, map[string]string{"prod/orders-prod.yaml": content})
+	}
+
+	base := time.Date(2025, 9, 1, 14, 0, 0, 0, time.UTC)
+	first := commitFile("timeout: 5\n", base)
+	second := commitFile("timeout: 10\n", base.Add(time.Hour))
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+
+	tests := []struct {
+		name    string
+		rev     Revision
+		commit  plumbing.Hash
+		timeout any
+	}{
+		{"At first commit", Revision{At: base.Add(5 * time.Minute)}, first, 5},
+		{"At second commit", Revision{At: base.Add(2 * time.Hour)}, second, 10},
+		{"Abbreviated commit", Revision{Commit: first.String()[:7]}, first, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := cs.LoadConfigAt("orders", "prod", "", tt.rev, LoadOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Version != tt.commit.String() || resp.LastCommit != tt.commit.String() {
+				t.Errorf("expected version and last commit %s, got %s / %s", tt.commit, resp.Version, resp.LastCommit)
+			}
+			if len(resp.PropertySources) != 1 || resp.PropertySources[0].Source["timeout"] != tt.timeout {
+				t.Errorf("unexpected property sources %+v", resp.PropertySources)
+			}
+		})
+	}
+
+	if _, err := cs.LoadConfigAt("orders", "prod", "main", Revision{At: base.Add(-time.Hour)}, LoadOptions{}); !stderrors.Is(err, errors.ErrNotFound) {
+		t.Errorf("expected ErrNotFound before the first commit, got %v", err)
+	}
+	if _, err := cs.LoadConfigAt("orders", "prod", "main", Revision{Commit: "deadbeef"}, LoadOptions{}); !stderrors.Is(err, errors.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown commit, got %v", err)
+	}
+}
+
+func TestConfigService_LoadConfig_StrictTypes(t *testing.T) {
+	tmpDir := t.TempDir()
+	envDir := filepath.Join(tmpDir, "main", "prod")
+	_ = os.MkdirAll(envDir, 0755)
+	_ = os.WriteFile(filepath.Join(envDir, "myapp-prod.properties"), []byte("zip=01234\n"), 0644)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main", StrictTypes: true}
+	repo := repository.NewGitRepo(tmpDir, "")
+	cs := NewConfigServiceFromRepo(repo, cfg)
+
+	resp := cs.LoadConfig("myapp", "prod", "main")
+	if len(resp.PropertySources) != 1 {
+		t.Fatalf("expected 1 property source, got %d", len(resp.PropertySources))
+	}
+	if got := resp.PropertySources[0].Source["zip"]; got != "01234" {
+		t.Errorf("expected zip to stay \"01234\", got %#v", got)
+	}
+}
+
+func TestConfigService_LoadConfigWithOptions_ArrayFlatten(t *testing.T) {
+	tmpDir := t.TempDir()
+	envDir := filepath.Join(tmpDir, "main", "prod")
+	_ = os.MkdirAll(envDir, 0755)
+	_ = os.WriteFile(filepath.Join(envDir, "myapp-prod.yaml"), []byte("servers:\n  - host: a\n  - host: b\n"), 0644)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main", ArrayFlatten: config.ArrayFlattenIndexed}
+	repo := repository.NewGitRepo(tmpDir, "")
+	cs := NewConfigServiceFromRepo(repo, cfg)
+
+	source := cs.LoadConfig("myapp", "prod", "main").PropertySources[0].Source
+	if source["servers[1].host"] != "b" {
+		t.Errorf("expected server setting to flatten lists by index, got %v", source)
+	}
+
+	source = cs.LoadConfigWithOptions("myapp", "prod", "main", LoadOptions{ArrayFlatten: config.ArrayFlattenRaw}).PropertySources[0].Source
+	if _, ok := source["servers"].([]any); !ok {
+		t.Errorf("expected request override to keep the raw list, got %v", source)
+	}
+}
+
+func TestConfigService_LoadConfig_TOML(t *testing.T) {
+	tmpDir := t.TempDir()
+	envDir := filepath.Join(tmpDir, "main", "prod")
+	_ = os.MkdirAll(envDir, 0755)
+	_ = os.WriteFile(filepath.Join(envDir, "myapp-prod.toml"), []byte("[server]\nport = 8080\n"), 0644)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	repo := repository.NewGitRepo(tmpDir, "")
+	cs := NewConfigServiceFromRepo(repo, cfg)
+
+	resp := cs.LoadConfig("myapp", "prod", "main")
+	if len(resp.PropertySources) != 1 || resp.PropertySources[0].Source["server.port"] != int64(8080) {
+		t.Errorf("expected TOML source with server.port, got %v", resp.PropertySources)
+	}
+}
+
+func TestConfigService_LoadConfig_MultiDocumentYAML(t *testing.T) {
+	tmpDir := t.TempDir()
+	envDir := filepath.Join(tmpDir, "main", "prod")
+	_ = os.MkdirAll(envDir, 0755)
+	content := "server:\n  port: 8080\n---\nspring.config.activate.on-profile: prod\nserver:\n  port: 80\n---\nspring.profiles: dev\ndebug: true\n"
+	_ = os.WriteFile(filepath.Join(envDir, "application.yml"), []byte(content), 0644)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	repo := repository.NewGitRepo(tmpDir, "")
+	cs := NewConfigServiceFromRepo(repo, cfg)
+
+	resp := cs.LoadConfig("myapp", "prod", "main")
+	if len(resp.PropertySources) != 2 {
+		t.Fatalf("expected 2 active documents, got %d: %v", len(resp.PropertySources), resp.PropertySources)
+	}
+
+	first, second := resp.PropertySources[0], resp.PropertySources[1]
+	if first.Name != "prod/application.yml (document #1)" || first.Source["server.port"] != 80 {
+		t.Errorf("expected the prod document first, got %+v", first)
+	}
+	if second.Name != "prod/application.yml (document #0)" || second.Source["server.port"] != 8080 {
+		t.Errorf("expected the default document last, got %+v", second)
+	}
+}
+
+func TestConfigService_LoadConfig_MultipleProfiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, f := range []struct{ dir, name, content string }{
+		{"prod", "myapp-prod.yaml", "db: prod"},
+		{"prod", "application.yaml", "common: prod"},
+		{"mysql", "myapp-mysql.yaml", "db: mysql"},
+		{"mysql", "application-mysql.yaml", "driver: mysql"},
+		{"mysql", "application.yaml", "common: mysql"},
+	} {
+		dir := filepath.Join(tmpDir, "main", f.dir)
+		_ = os.MkdirAll(dir, 0755)
+		_ = os.WriteFile(filepath.Join(dir, f.name), []byte(f.content), 0644)
+	}
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main", SourceNames: config.SourceNamesPath}
+	repo := repository.NewGitRepo(tmpDir, "")
+	cs := NewConfigServiceFromRepo(repo, cfg)
+
+	resp := cs.LoadConfig("myapp", "prod, mysql,eu-west", "main")
+	if want := []string{"prod", "mysql", "eu-west"}; !reflect.DeepEqual(resp.Profiles, want) {
+		t.Errorf("expected profiles %v, got %v", want, resp.Profiles)
+	}
+
+	var names []string
+	for _, ps := range resp.PropertySources {
+		names = append(names, ps.Name)
+	}
+	want := []string{
+		"mysql/myapp-mysql.yaml",
+		"mysql/application-mysql.yaml",
+		"prod/myapp-prod.yaml",
+		"mysql/application.yaml",
+		"prod/application.yaml",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected sources %v, got %v", want, names)
+	}
+
+	t.Run("File names", func(t *testing.T) {
+		cs := NewConfigServiceFromRepo(repo, &config.Config{RepoPath: tmpDir, DefaultBranch: "main"})
+		var names []string
+		for _, ps := range cs.LoadConfig("myapp", "prod,mysql", "main").PropertySources {
+			names = append(names, ps.Name)
+		}
+		want := []string{"myapp-mysql.yaml", "application-mysql.yaml", "myapp-prod.yaml", "application.yaml", "application.yaml"}
+		if !reflect.DeepEqual(names, want) {
+			t.Errorf("expected file names %v, got %v", want, names)
+		}
+	})
+
+	t.Run("Unsafe profile", func(t *testing.T) {
+		resp := cs.LoadConfig("myapp", "prod,../mysql", "main")
+		if len(resp.PropertySources) != 0 {
+			t.Errorf("expected no sources for unsafe profile, got %v", resp.PropertySources)
+		}
+	})
+}
+
+func TestConfigService_GenerateConfigCandidates_ExactNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	envDir := filepath.Join(tmpDir, "main", "prod")
+	_ = os.MkdirAll(envDir, 0755)
+	for _, name := range []string{
+		"pay-prod.yaml",
+		"pay-prod.properties",
+		"pay-prod.json",
+		"pay-production.yaml",
+		"pay-prod-legacy.yaml",
+		"payments-prod.yaml",
+		"application-prod.yml",
+		"application-production.yml",
+		"application.yaml",
+		"application.bak.yaml",
+		"application.yaml.orig",
+		"Application.yaml",
+		"application",
+	} {
+		_ = os.WriteFile(filepath.Join(envDir, name), []byte("key: value"), 0644)
+	}
+	_ = os.MkdirAll(filepath.Join(envDir, "application.yml"), 0755)
+
+	cs := NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), &config.Config{RepoPath: tmpDir})
+
+	got, err := cs.generateConfigCandidates(os.DirFS(filepath.Join(tmpDir, "main")), "pay", []string{"prod"}, "main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"prod/pay-prod.properties",
+		"prod/pay-prod.yaml",
+		"prod/pay-prod.json",
+		"prod/application-prod.yml",
+		"prod/application.yaml",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected candidates %v, got %v", want, got)
+	}
+
+	t.Run("App named application", func(t *testing.T) {
+		got, err := cs.generateConfigCandidates(os.DirFS(filepath.Join(tmpDir, "main")), "application", []string{"prod"}, "main")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []string{"prod/application-prod.yml", "prod/application.yaml"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected candidates %v, got %v", want, got)
+		}
+	})
+}
+
+func TestConfigService_LoadConfig_SharedConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, f := range []struct{ dir, name string }{
+		{"prod", "myapp-prod.yaml"},
+		{"prod", "application.yaml"},
+		{"shared", "myapp.yaml"},
+		{"shared", "application.yaml"},
+		{"", "myapp.properties"},
+		{"", "application.yml"},
+		{"", "myapp-prod.yaml"},
+	} {
+		dir := filepath.Join(tmpDir, "main", f.dir)
+		_ = os.MkdirAll(dir, 0755)
+		_ = os.WriteFile(filepath.Join(dir, f.name), []byte("key: value"), 0644)
+	}
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main", SourceNames: config.SourceNamesPath, SharedPaths: []string{"shared", "../outside", "/"}}
+	repo := repository.NewGitRepo(tmpDir, "")
+	cs := NewConfigServiceFromRepo(repo, cfg)
+
+	resp := cs.LoadConfig("myapp", "prod", "main")
+	var names []string
+	for _, ps := range resp.PropertySources {
+		names = append(names, ps.Name)
+	}
+	want := []string{
+		"prod/myapp-prod.yaml",
+		"prod/application.yaml",
+		"shared/myapp.yaml",
+		"shared/application.yaml",
+		"myapp.properties",
+		"application.yml",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected sources %v, got %v", want, names)
+	}
+
+	t.Run("Missing env directory", func(t *testing.T) {
+		// shared files alone are not a config for an unknown env
+		resp := cs.LoadConfig("myapp", "staging", "main")
+		if len(resp.PropertySources) != 0 {
+			t.Errorf("expected no sources, got %v", resp.PropertySources)
+		}
+	})
+}
+
+func TestConfigService_LoadConfig_SearchPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, f := range []struct{ dir, name string }{
+		{"services/myapp/prod", "myapp-prod.yaml"},
+		{"services/myapp/prod", "application.yaml"},
+		{"teams/a/myapp/prod", "application-prod.yaml"},
+		{"teams/b/myapp/prod", "myapp-prod.yml"},
+		{"teams/.hidden/myapp/prod", "myapp-prod.yaml"},
+		{"prod", "myapp-prod.yaml"},
+		{"services/myapp", "myapp.yaml"},
+	} {
+		dir := filepath.Join(tmpDir, "main", filepath.FromSlash(f.dir))
+		_ = os.MkdirAll(dir, 0755)
+		_ = os.WriteFile(filepath.Join(dir, f.name), []byte("key: value"), 0644)
+	}
+
+	cfg := &config.Config{
+		RepoPath:      tmpDir,
+		DefaultBranch: "main",
+		SearchPaths:   []string{"services/{application}/{profile}", "teams/*/{application}/{profile}", "../{profile}"},
+		SharedPaths:   []string{"services/{application}", "{profile}"},
+		SourceNames:   config.SourceNamesPath,
+	}
+	repo := repository.NewGitRepo(tmpDir, "")
+	cs := NewConfigServiceFromRepo(repo, cfg)
+
+	resp := cs.LoadConfig("myapp", "prod", "main")
+	var names []string
+	for _, ps := range resp.PropertySources {
+		names = append(names, ps.Name)
+	}
+	want := []string{
+		"services/myapp/prod/myapp-prod.yaml",
+		"teams/a/myapp/prod/application-prod.yaml",
+		"teams/b/myapp/prod/myapp-prod.yml",
+		"services/myapp/prod/application.yaml",
+		"services/myapp/myapp.yaml",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected sources %v, got %v", want, names)
+	}
+}
+
+// testRepo is a git repository for tests, on branch main.
+type testRepo struct {
+	t   *testing.T
+	dir string
+	wt  *git.Worktree
+}
+
+func newTestRepo(t *testing.T, dir string) *testRepo {
+	t.Helper()
+	repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.Main},
+	})
+	if err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	return &testRepo{t: t, dir: dir, wt: wt}
+}
+
+// commit writes files, relative to the repository root, and commits them at when.
+func (r *testRepo) commit(msg string, when time.Time, files map[string]string) plumbing.Hash {
+	r.t.Helper()
+	return r.commitAs("Test", msg, when, files)
+}
+
+// commitAs is commit by author. A file with empty content is removed.
+func (r *testRepo) commitAs(author, msg string, when time.Time, files map[string]string) plumbing.Hash {
+	r.t.Helper()
+	for name, content := range files {
+		if content == "" {
+			_, _ = r.wt.Remove(name)
+			continue
+		}
+		full := filepath.Join(r.dir, filepath.FromSlash(name))
+		_ = os.MkdirAll(filepath.Dir(full), 0755)
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			r.t.Fatalf("failed to write %s: %v", name, err)
+		}
+		_, _ = r.wt.Add(name)
+	}
+	hash, err := r.wt.Commit(msg, &git.CommitOptions{
+		Author: &object.Signature{Name: author, Email: author + "@example.com", When: when},
+	})
+	if err != nil {
+		r.t.Fatalf("failed to commit: %v", err)
+	}
+	return hash
+}
+
+// cloneTestRepo clones branch main of url into dir, keeping depth commits of history
+// when depth is positive, as production clones do.
+func cloneTestRepo(t *testing.T, url, dir string, depth int) {
+	t.Helper()
+	_, err := git.PlainClone(dir, false, &git.CloneOptions{
+		URL:           url,
+		ReferenceName: plumbing.NewBranchReferenceName("main"),
+		SingleBranch:  true,
+		Depth:         depth,
+	})
+	if err != nil {
+		t.Fatalf("failed to clone: %v", err)
+	}
+}
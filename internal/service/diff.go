@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect service
+ * https://github.com/KAnggara75/conflect/tree/main/internal/service
+ */
+
+package service
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/errors"
+)
+
+// ConfigRef selects a resolved config: an env (one or more comma-separated
+// profiles) on a label, optionally at a commit of that label.
+type ConfigRef struct {
+	Env    string
+	Label  string
+	Commit string
+}
+
+// DiffConfig compares the effective config of appName at two refs and returns the
+// added, removed and changed keys. Values of secret keys are masked.
+func (c *ConfigService) DiffConfig(appName string, from, to ConfigRef, opts LoadOptions) (*dto.DiffResponse, error) {
+	before := c.loadAt(appName, from.Env, from.Label, Revision{Commit: from.Commit}, opts)
+	after := c.loadAt(appName, to.Env, to.Label, Revision{Commit: to.Commit}, opts)
+
+	for _, side := range []*loadedConfig{before, after} {
+		if side.err != nil {
+			return nil, side.err
+		}
+		if side.response.Error != "" {
+			return nil, fmt.Errorf("%w: %s", errors.ErrInvalidInput, side.response.Error)
+		}
+	}
+	if len(before.response.PropertySources) == 0 && len(after.response.PropertySources) == 0 {
+		return nil, fmt.Errorf("%w: config for %s", errors.ErrNotFound, appName)
+	}
+
+	diff := &dto.DiffResponse{
+		Name:    appName,
+		From:    diffSide(before.response),
+		To:      diffSide(after.response),
+		Added:   make(map[string]any),
+		Removed: make(map[string]any),
+		Changed: make(map[string]dto.ValueChange),
+	}
+
+	oldValues := MergeSources(before.response.PropertySources)
+	newValues := MergeSources(after.response.PropertySources)
+	c.diffValues(oldValues, newValues, diff.Added, diff.Removed, diff.Changed)
+
+	return diff, nil
+}
+
+// diffValues fills added, removed and changed with the masked differences between
+// two merged configs.
+func (c *ConfigService) diffValues(oldValues, newValues map[string]any, added, removed map[string]any, changed map[string]dto.ValueChange) {
+	for key, v := range newValues {
+		old, ok := oldValues[key]
+		switch {
+		case !ok:
+			added[key] = c.mask(key, v)
+		case !reflect.DeepEqual(old, v):
+			changed[key] = dto.ValueChange{From: c.mask(key, old), To: c.mask(key, v)}
+		}
+	}
+	for key, v := range oldValues {
+		if _, ok := newValues[key]; !ok {
+			removed[key] = c.mask(key, v)
+		}
+	}
+}
+
+func diffSide(resp *dto.ConfigResponse) dto.DiffSide {
+	return dto.DiffSide{Profiles: resp.Profiles, Label: resp.Label, Version: resp.Version}
+}
+
+// MergeSources flattens property sources into one map; earlier sources win.
+func MergeSources(sources []dto.PropertySource) map[string]any {
+	merged := make(map[string]any)
+	for _, ps := range sources {
+		for k, v := range ps.Source {
+			if _, ok := merged[k]; !ok {
+				merged[k] = v
+			}
+		}
+	}
+	return merged
+}
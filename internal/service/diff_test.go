@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package service
+
+import (
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/errors"
+	"github.com/KAnggara75/conflect/internal/repository"
+)
+
+func TestConfigService_DiffConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := newTestRepo(t, filepath.Join(tmpDir, "main"))
+
+	first := repo.commit("first", time.Now(), map[string]string{
+		"prod/myapp-prod.yaml":       "db:\n  host: a\n  password: old\nlog: info\n",
+		"staging/myapp-staging.yaml": "db:\n  host: staging\n  password: old\n",
+	}).String()
+	repo.commit("second", time.Now(), map[string]string{
+		"prod/myapp-prod.yaml": "db:\n  host: b\n  password: new\nfeature: true\n",
+	})
+
+	releaseDir := filepath.Join(tmpDir, "release")
+	_ = os.MkdirAll(filepath.Join(releaseDir, "prod"), 0755)
+	_ = os.WriteFile(filepath.Join(releaseDir, "prod", "myapp-prod.yaml"), []byte("db:\n  host: b\n  password: new\n"), 0644)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+
+	t.Run("Commits", func(t *testing.T) {
+		diff, err := cs.DiffConfig("myapp", ConfigRef{Env: "prod", Label: "main", Commit: first[:7]}, ConfigRef{Env: "prod", Label: "main"}, LoadOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff.From.Version != first {
+			t.Errorf("expected from version %s, got %s", first, diff.From.Version)
+		}
+		if want := map[string]any{"feature": true}; !reflect.DeepEqual(diff.Added, want) {
+			t.Errorf("added = %v, want %v", diff.Added, want)
+		}
+		if want := map[string]any{"log": "info"}; !reflect.DeepEqual(diff.Removed, want) {
+			t.Errorf("removed = %v, want %v", diff.Removed, want)
+		}
+		want := map[string]dto.ValueChange{
+			"db.host":     {From: "a", To: "b"},
+			"db.password": {From: MaskedValue, To: MaskedValue},
+		}
+		if !reflect.DeepEqual(diff.Changed, want) {
+			t.Errorf("changed = %v, want %v", diff.Changed, want)
+		}
+	})
+
+	t.Run("Labels", func(t *testing.T) {
+		diff, err := cs.DiffConfig("myapp", ConfigRef{Env: "prod", Label: "release"}, ConfigRef{Env: "prod", Label: "main"}, LoadOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(diff.Changed) != 0 || len(diff.Removed) != 0 || !reflect.DeepEqual(diff.Added, map[string]any{"feature": true}) {
+			t.Errorf("unexpected diff %+v", diff)
+		}
+	})
+
+	t.Run("Envs", func(t *testing.T) {
+		diff, err := cs.DiffConfig("myapp", ConfigRef{Env: "staging", Label: "main"}, ConfigRef{Env: "prod", Label: "main"}, LoadOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]dto.ValueChange{
+			"db.host":     {From: "staging", To: "b"},
+			"db.password": {From: MaskedValue, To: MaskedValue},
+		}
+		if !reflect.DeepEqual(diff.Changed, want) {
+			t.Errorf("changed = %v, want %v", diff.Changed, want)
+		}
+	})
+
+	t.Run("Nested secrets", func(t *testing.T) {
+		// raw lists keep their objects whole, so secrets are masked inside them
+		_ = os.WriteFile(filepath.Join(releaseDir, "prod", "lists-prod.yaml"), []byte("datasources:\n  - url: a\n    password: prodpass\n"), 0644)
+		_ = os.WriteFile(filepath.Join(tmpDir, "main", "prod", "lists-prod.yaml"), []byte("datasources:\n  - url: b\n    password: devpass\n"), 0644)
+
+		diff, err := cs.DiffConfig("lists", ConfigRef{Env: "prod", Label: "release"}, ConfigRef{Env: "prod", Label: "main"}, LoadOptions{ArrayFlatten: config.ArrayFlattenRaw})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]dto.ValueChange{
+			"datasources": {
+				From: []any{map[string]any{"url": "a", "password": MaskedValue}},
+				To:   []any{map[string]any{"url": "b", "password": MaskedValue}},
+			},
+		}
+		if !reflect.DeepEqual(diff.Changed, want) {
+			t.Errorf("changed = %v, want %v", diff.Changed, want)
+		}
+	})
+
+	t.Run("Unknown commit", func(t *testing.T) {
+		_, err := cs.DiffConfig("myapp", ConfigRef{Env: "prod", Label: "main", Commit: "deadbeef"}, ConfigRef{Env: "prod", Label: "main"}, LoadOptions{})
+		if !stderrors.Is(err, errors.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Unknown app", func(t *testing.T) {
+		_, err := cs.DiffConfig("other", ConfigRef{Env: "prod", Label: "main"}, ConfigRef{Env: "prod", Label: "release"}, LoadOptions{})
+		if !stderrors.Is(err, errors.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestConfigService_IsSecretKey(t *testing.T) {
+	cs := &ConfigService{cfg: &config.Config{}}
+	for key, want := range map[string]bool{
+		"db.password":      true,
+		"spring.api-key":   true,
+		"client.apiKey":    true,
+		"GITHUB_TOKEN":     true,
+		"db.host":          false,
+		"credentials.user": true,
+	} {
+		if got := cs.isSecretKey(key); got != want {
+			t.Errorf("isSecretKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+
+	cs = &ConfigService{cfg: &config.Config{SecretKeys: []string{"host"}}}
+	if !cs.isSecretKey("db.host") || cs.isSecretKey("db.password") {
+		t.Error("expected configured SecretKeys to replace the defaults")
+	}
+}
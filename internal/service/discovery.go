@@ -0,0 +1,233 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect service
+ * https://github.com/KAnggara75/conflect/tree/main/internal/service
+ */
+
+package service
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/KAnggara75/conflect/internal/errors"
+)
+
+// ListLabels returns the labels with a local clone, sorted.
+func (c *ConfigService) ListLabels() ([]string, error) {
+	branches, err := c.repo.ListLocalBranches()
+	if err != nil {
+		return nil, err
+	}
+
+	var labels []string
+	for _, branch := range branches {
+		if isSafePathComponent(branch) && !strings.HasPrefix(branch, ".") {
+			labels = append(labels, branch)
+		}
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
+
+// ListApplications maps each environment found on label to the applications with a
+// {app}-{env}.* config file in a search directory. application-{env}.* files add the
+// environment without an application. The environment is the {profile} of the search
+// path the file was found through, so eu-west/orders-eu-west.yaml is orders in eu-west;
+// the application is likewise taken from {application}. Only when the search path
+// names neither is the file name split at its last dash.
+func (c *ConfigService) ListApplications(label string) (map[string][]string, error) {
+	if label == "" {
+		label = c.cfg.DefaultBranch
+	}
+	if !isSafePathComponent(label) {
+		return nil, fmt.Errorf("%w: label %q", errors.ErrInvalidInput, label)
+	}
+
+	root := filepath.Join(c.repo.Path, label)
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%w: label %s", errors.ErrNotFound, label)
+	}
+
+	var patterns []*searchPattern
+	for _, tmpl := range c.searchPaths() {
+		if p := compileSearchPattern(tmpl, label); p != nil {
+			patterns = append(patterns, p)
+		}
+	}
+
+	envs := make(map[string][]string)
+	err := fs.WalkDir(os.DirFS(root), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != "." && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		ext := path.Ext(d.Name())
+		if !slices.Contains(configExtensions, ext) {
+			return nil
+		}
+		stem := strings.TrimSuffix(d.Name(), ext)
+
+		app, env, ok := "", "", false
+		for _, pattern := range patterns {
+			if app, env, ok = pattern.split(path.Dir(p), stem); ok {
+				break
+			}
+		}
+		if !ok || !isSafePathComponent(app) || !isSafePathComponent(env) {
+			return nil
+		}
+		if app == "application" {
+			if _, ok := envs[env]; !ok {
+				envs[env] = []string{}
+			}
+			return nil
+		}
+		if !slices.Contains(envs[env], app) {
+			envs[env] = append(envs[env], app)
+		}
+		return nil
+	})
+	if err != nil && !stderrors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to scan label %s: %w", label, err)
+	}
+
+	for _, apps := range envs {
+		sort.Strings(apps)
+	}
+	return envs, nil
+}
+
+// searchPattern matches the directories a search-path template yields, capturing the
+// values of its {application} and {profile} placeholders.
+type searchPattern struct {
+	re *regexp.Regexp
+}
+
+// compileSearchPattern turns tmpl into a searchPattern for label, or returns nil for
+// templates that searchDirs would skip as unsafe.
+func compileSearchPattern(tmpl, label string) *searchPattern {
+	tmpl = strings.Trim(strings.TrimSpace(tmpl), "/")
+	if !isSafeSearchPath(tmpl) {
+		return nil
+	}
+	if tmpl == "" {
+		tmpl = "."
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(tmpl); {
+		switch rest := tmpl[i:]; {
+		case strings.HasPrefix(rest, "{application}"):
+			b.WriteString("(?P<application>[^/]+)")
+			i += len("{application}")
+		case strings.HasPrefix(rest, "{profile}"):
+			b.WriteString("(?P<profile>[^/]+)")
+			i += len("{profile}")
+		case strings.HasPrefix(rest, "{label}"):
+			b.WriteString(regexp.QuoteMeta(label))
+			i += len("{label}")
+		case rest[0] == '*':
+			b.WriteString("[^/]*")
+			i++
+		case rest[0] == '?':
+			b.WriteString("[^/]")
+			i++
+		case rest[0] == '[' && strings.IndexByte(rest, ']') > 1:
+			class := rest[1:strings.IndexByte(rest, ']')]
+			if strings.HasPrefix(class, "^") || strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += len(class) + 2
+		default:
+			b.WriteString(regexp.QuoteMeta(rest[:1]))
+			i++
+		}
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil
+	}
+	return &searchPattern{re: re}
+}
+
+// split returns the application and profile of the config file stem found in dir,
+// reporting false when dir is not one of the pattern's directories or stem is not an
+// {app}-{profile} name of them. A placeholder used twice must capture one value.
+func (p *searchPattern) split(dir, stem string) (app, profile string, ok bool) {
+	match := p.re.FindStringSubmatch(dir)
+	if match == nil {
+		return "", "", false
+	}
+	for i, name := range p.re.SubexpNames() {
+		var value *string
+		switch name {
+		case "application":
+			value = &app
+		case "profile":
+			value = &profile
+		default:
+			continue
+		}
+		if *value != "" && *value != match[i] {
+			return "", "", false
+		}
+		*value = match[i]
+	}
+
+	switch {
+	case profile != "":
+		if !strings.HasSuffix(stem, "-"+profile) {
+			return "", "", false
+		}
+		name := strings.TrimSuffix(stem, "-"+profile)
+		if name != "application" && app != "" && name != app {
+			return "", "", false
+		}
+		return name, profile, name != ""
+	case app != "":
+		if env, found := strings.CutPrefix(stem, app+"-"); found {
+			return app, env, env != ""
+		}
+		if env, found := strings.CutPrefix(stem, "application-"); found {
+			return "application", env, env != ""
+		}
+		return "", "", false
+	default:
+		i := strings.LastIndexByte(stem, '-')
+		if i <= 0 || i == len(stem)-1 {
+			return "", "", false
+		}
+		return stem[:i], stem[i+1:], true
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package service
+
+import (
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/errors"
+	"github.com/KAnggara75/conflect/internal/repository"
+)
+
+func TestConfigService_Discovery(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := []string{
+		"main/prod/orders-prod.yaml",
+		"main/prod/billing-prod.properties",
+		"main/prod/application.yaml",
+		"main/staging/orders-staging.yml",
+		"main/services/my-app/my-app-staging.json",
+		"main/qa/application-qa.yaml",
+		"main/eu-west/orders-eu-west.yaml",
+		"main/eu-west/application-eu-west.yaml",
+		"main/eu-west/billing-prod.yaml",
+		"main/docs/orders-draft.yaml",
+		"main/prod/README.md",
+		"main/.git/config-prod.yaml",
+		"release/prod/orders-prod.yaml",
+	}
+	for _, f := range files {
+		full := filepath.Join(tmpDir, filepath.FromSlash(f))
+		_ = os.MkdirAll(filepath.Dir(full), 0755)
+		_ = os.WriteFile(full, []byte("a: 1"), 0644)
+	}
+	_ = os.MkdirAll(filepath.Join(tmpDir, ".cache"), 0755)
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main", SearchPaths: []string{"{profile}", "services/{application}"}}
+	cs := NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+
+	labels, err := cs.ListLabels()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"main", "release"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("ListLabels() = %v, want %v", labels, want)
+	}
+
+	envs, err := cs.ListApplications("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]string{
+		"prod":    {"billing", "orders"},
+		"staging": {"my-app", "orders"},
+		"qa":      {},
+		"eu-west": {"orders"},
+	}
+	if !reflect.DeepEqual(envs, want) {
+		t.Errorf("ListApplications() = %v, want %v", envs, want)
+	}
+
+	if _, err := cs.ListApplications("missing"); !stderrors.Is(err, errors.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := cs.ListApplications(".."); !stderrors.Is(err, errors.ErrInvalidInput) {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+}
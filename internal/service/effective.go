@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect service
+ * https://github.com/KAnggara75/conflect/tree/main/internal/service
+ */
+
+package service
+
+import (
+	"log"
+	"path"
+	"reflect"
+
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/helper"
+)
+
+// EffectiveConfig merges the property sources of appName/env/label into one value per
+// key. Each key records the winning source, the commit that last changed its value in
+// that source's file, and the values it hides in lower-priority sources.
+func (c *ConfigService) EffectiveConfig(appName, env, label string, opts LoadOptions) *dto.EffectiveConfigResponse {
+	loaded := c.load(appName, env, label, opts)
+	resp := loaded.response
+
+	effective := &dto.EffectiveConfigResponse{
+		Name:       resp.Name,
+		Profiles:   resp.Profiles,
+		Label:      resp.Label,
+		Version:    resp.Version,
+		LastCommit: resp.LastCommit,
+		Properties: make(map[string]dto.EffectiveProperty),
+		Error:      resp.Error,
+	}
+
+	// keys won by each file, so history is only read once per file
+	wonBy := make(map[string][]string)
+
+	for i, ps := range resp.PropertySources {
+		for key, value := range ps.Source {
+			prop, ok := effective.Properties[key]
+			if !ok {
+				effective.Properties[key] = dto.EffectiveProperty{Value: value, Source: ps.Name}
+				wonBy[loaded.origins[i]] = append(wonBy[loaded.origins[i]], key)
+				continue
+			}
+			prop.Overrides = append(prop.Overrides, dto.OverriddenValue{Value: value, Source: ps.Name})
+			effective.Properties[key] = prop
+		}
+	}
+
+	// per-key commits need history older than a shallow clone has
+	if len(wonBy) > 0 {
+		if err := c.repo.FetchHistory(resp.Label); err != nil {
+			log.Println(err)
+		}
+	}
+
+	for file, keys := range wonBy {
+		commits := c.keyCommits(resp.Label, file, resp.Profiles, loaded.parseOpts, keys)
+		for _, key := range keys {
+			prop := effective.Properties[key]
+			prop.Commit = commits[key]
+			effective.Properties[key] = prop
+		}
+	}
+
+	return effective
+}
+
+// keyCommits walks the history of file and returns, for each key, the commit that
+// set its current value: the oldest commit in the run of newest revisions that all
+// hold the same value. Keys whose history cannot be read are left out.
+func (c *ConfigService) keyCommits(label, file string, profiles []string, parseOpts helper.ParseOptions, keys []string) map[string]string {
+	commits := make(map[string]string, len(keys))
+
+	revisions, err := c.repo.FileHistory(label, file)
+	if err != nil {
+		log.Printf("failed to read history of %s: %v", file, err)
+		return commits
+	}
+	if len(revisions) == 0 {
+		return commits
+	}
+
+	ext := path.Ext(file)
+	views := make([]map[string]any, 0, len(revisions))
+	for _, rev := range revisions {
+		view, err := activeView(rev.Data, ext, profiles, parseOpts)
+		if err != nil {
+			// an unparsable revision ends the walk, as if every key changed there
+			break
+		}
+		views = append(views, view)
+	}
+	if len(views) == 0 {
+		return commits
+	}
+
+	for _, key := range keys {
+		current, ok := views[0][key]
+		if !ok {
+			continue
+		}
+		commit := revisions[0].Commit
+		for j := 1; j < len(views); j++ {
+			v, ok := views[j][key]
+			if !ok || !reflect.DeepEqual(v, current) {
+				break
+			}
+			commit = revisions[j].Commit
+		}
+		commits[key] = commit
+	}
+
+	return commits
+}
+
+// activeView parses data and merges its documents that are active for profiles,
+// later documents winning, into one flat map.
+func activeView(data []byte, ext string, profiles []string, parseOpts helper.ParseOptions) (map[string]any, error) {
+	docs, err := helper.ParseDocuments(data, ext, parseOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	view := make(map[string]any)
+	for _, doc := range docs {
+		active, err := helper.MatchesProfiles(doc.Profiles, profiles)
+		if err != nil {
+			return nil, err
+		}
+		if !active {
+			continue
+		}
+		for k, v := range doc.Source {
+			view[k] = v
+		}
+	}
+	return view, nil
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package service
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/repository"
+)
+
+func TestConfigService_EffectiveConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := newTestRepo(t, filepath.Join(tmpDir, "main"))
+
+	now := time.Now()
+	first := repo.commit("first", now.Add(-2*time.Minute), map[string]string{
+		"prod/myapp-prod.yaml":  "db:\n  host: a\n  port: 5432\n",
+		"prod/application.yaml": "db:\n  host: localhost\nlog: info\n",
+	})
+	second := repo.commit("second", now.Add(-time.Minute), map[string]string{
+		"prod/myapp-prod.yaml": "db:\n  host: b\n  port: 5432\n",
+	})
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+
+	resp := cs.EffectiveConfig("myapp", "prod", "main", LoadOptions{})
+	if resp.Error != "" {
+		t.Fatalf("unexpected error: %s", resp.Error)
+	}
+	if len(resp.Properties) != 3 {
+		t.Fatalf("expected 3 merged keys, got %v", resp.Properties)
+	}
+
+	host := resp.Properties["db.host"]
+	if host.Value != "b" || host.Source != "myapp-prod.yaml" || host.Commit != second.String() {
+		t.Errorf("unexpected db.host provenance %+v", host)
+	}
+	if len(host.Overrides) != 1 || host.Overrides[0] != (dto.OverriddenValue{Value: "localhost", Source: "application.yaml"}) {
+		t.Errorf("expected db.host to override localhost, got %+v", host.Overrides)
+	}
+
+	if port := resp.Properties["db.port"]; port.Commit != first.String() || len(port.Overrides) != 0 {
+		t.Errorf("expected db.port set by first commit without overrides, got %+v", port)
+	}
+	if lvl := resp.Properties["log"]; lvl.Value != "info" || lvl.Source != "application.yaml" || lvl.Commit != first.String() {
+		t.Errorf("unexpected log provenance %+v", lvl)
+	}
+}
+
+func TestConfigService_EffectiveConfig_Shallow(t *testing.T) {
+	originDir := t.TempDir()
+	origin := newTestRepo(t, originDir)
+	now := time.Now()
+	first := origin.commit("first", now.Add(-2*time.Minute), map[string]string{
+		"prod/myapp-prod.yaml": "db:\n  host: a\n  port: 5432\n",
+	})
+	second := origin.commit("second", now.Add(-time.Minute), map[string]string{
+		"prod/myapp-prod.yaml": "db:\n  host: b\n  port: 5432\n",
+	})
+
+	localDir := t.TempDir()
+	cloneTestRepo(t, originDir, filepath.Join(localDir, "main"), 1)
+
+	cfg := &config.Config{RepoPath: localDir, DefaultBranch: "main"}
+	cs := NewConfigServiceFromRepo(repository.NewGitRepo(localDir, originDir), cfg)
+
+	resp := cs.EffectiveConfig("myapp", "prod", "main", LoadOptions{})
+	if host := resp.Properties["db.host"]; host.Commit != second.String() {
+		t.Errorf("expected db.host from %s, got %+v", second, host)
+	}
+	if port := resp.Properties["db.port"]; port.Commit != first.String() {
+		t.Errorf("expected db.port from %s past the clone depth, got %+v", first, port)
+	}
+}
@@ -0,0 +1,201 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect service
+ * https://github.com/KAnggara75/conflect/tree/main/internal/service
+ */
+
+package service
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"sort"
+
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+)
+
+// Selection picks the config of an application for an env, which may list several
+// comma-separated profiles, on a label. An empty label is the default branch.
+type Selection struct {
+	App   string
+	Env   string
+	Label string
+}
+
+// ChangeStream follows the resolved configs of a set of selections across branch
+// updates. Its position is the commit each label was last read at, which Cursor
+// encodes so that a reconnecting client can resume where it left off.
+type ChangeStream struct {
+	c          *ConfigService
+	opts       LoadOptions
+	selections []Selection
+	configs    []dto.ChangeEvent
+	values     []map[string]any
+	reset      []bool
+	heads      map[string]string
+	updated    map[string]<-chan struct{}
+}
+
+// NewChangeStream starts following selections. When cursor comes from an earlier
+// stream, it also returns the changes made since then. A config whose commit in the
+// cursor cannot be read gets a reset event listing every current key; a malformed
+// cursor starts afresh. Resuming never fetches history, so on a shallow clone a
+// cursor older than the clone depth also gets a reset event.
+func (c *ConfigService) NewChangeStream(selections []Selection, cursor string, opts LoadOptions) (*ChangeStream, []dto.ChangeEvent) {
+	s := &ChangeStream{
+		c:          c,
+		opts:       opts,
+		selections: make([]Selection, len(selections)),
+		configs:    make([]dto.ChangeEvent, len(selections)),
+		values:     make([]map[string]any, len(selections)),
+		reset:      make([]bool, len(selections)),
+		heads:      make(map[string]string),
+		updated:    make(map[string]<-chan struct{}),
+	}
+	for i, sel := range selections {
+		if sel.Label == "" {
+			sel.Label = c.cfg.DefaultBranch
+		}
+		s.selections[i] = sel
+		// subscribe before loading, as in WatchConfig
+		if _, ok := s.updated[sel.Label]; !ok {
+			s.updated[sel.Label] = c.updates.wait(sel.Label)
+			s.heads[sel.Label], _ = c.GetBranchSHA(sel.Label)
+		}
+	}
+
+	resumed, _ := url.ParseQuery(cursor)
+	baseline := opts
+	baseline.skipFetch = true
+	baseline.skipLastCommit = true
+	for i, sel := range s.selections {
+		if commit := resumed.Get(sel.Label); commit != "" && commit != s.heads[sel.Label] {
+			if before, err := c.LoadConfigAt(sel.App, sel.Env, sel.Label, Revision{Commit: commit}, baseline); err == nil && before.Error == "" {
+				s.values[i] = MergeSources(before.PropertySources)
+			} else {
+				s.values[i] = map[string]any{}
+				s.reset[i] = true
+			}
+		}
+	}
+
+	var changes []dto.ChangeEvent
+	seen := make(map[string]bool)
+	for _, sel := range s.selections {
+		if seen[sel.Label] {
+			continue
+		}
+		seen[sel.Label] = true
+		events := s.reload(sel.Label)
+		// only a resumed label has anything to catch up on
+		if resumed.Get(sel.Label) != "" {
+			changes = append(changes, events...)
+		}
+	}
+	return s, changes
+}
+
+// Configs returns the name, profiles, label and version of each selected config as
+// last read.
+func (s *ChangeStream) Configs() []dto.ChangeEvent {
+	return append([]dto.ChangeEvent(nil), s.configs...)
+}
+
+// Cursor encodes the commit each label of the stream was last read at.
+func (s *ChangeStream) Cursor() string {
+	cursor := url.Values{}
+	for label, head := range s.heads {
+		if head != "" {
+			cursor.Set(label, head)
+		}
+	}
+	return cursor.Encode()
+}
+
+// Next waits for a branch update and returns the selected configs it changed, which
+// may be none. It reports false when ctx ends first.
+func (s *ChangeStream) Next(ctx context.Context) ([]dto.ChangeEvent, bool) {
+	labels := make([]string, 0, len(s.updated))
+	cases := []reflect.SelectCase{{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}}
+	for label, ch := range s.updated {
+		labels = append(labels, label)
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ch)})
+	}
+
+	chosen, _, _ := reflect.Select(cases)
+	if chosen == 0 {
+		return nil, false
+	}
+
+	label := labels[chosen-1]
+	s.updated[label] = s.c.updates.wait(label)
+	s.heads[label], _ = s.c.GetBranchSHA(label)
+	return s.reload(label), true
+}
+
+// reload re-reads the selections on label and returns one event per config whose
+// effective keys changed, or whose error changed. A config that fails to load keeps
+// its last good values, so the event after the fix lists every key fixed since. A
+// config to reset always gets an event.
+func (s *ChangeStream) reload(label string) []dto.ChangeEvent {
+	var events []dto.ChangeEvent
+	for i, sel := range s.selections {
+		if sel.Label != label {
+			continue
+		}
+
+		resp := s.c.LoadConfigWithOptions(sel.App, sel.Env, sel.Label, s.opts)
+		prev := s.configs[i]
+		current := dto.ChangeEvent{Name: sel.App, Profiles: resp.Profiles, Label: sel.Label, Version: resp.Version, Error: resp.Error}
+		s.configs[i] = current
+		current.Reset = s.reset[i]
+		s.reset[i] = false
+
+		if resp.Error != "" {
+			if resp.Error != prev.Error || current.Reset {
+				events = append(events, current)
+			}
+			continue
+		}
+
+		values := MergeSources(resp.PropertySources)
+		current.ChangedKeys = changedKeys(s.values[i], values)
+		s.values[i] = values
+		if len(current.ChangedKeys) > 0 || prev.Error != "" || current.Reset {
+			events = append(events, current)
+		}
+	}
+	return events
+}
+
+// changedKeys lists, sorted, the keys added, removed or changed between two merged
+// configs. A nil oldValues has nothing to compare and yields none.
+func changedKeys(oldValues, newValues map[string]any) []string {
+	if oldValues == nil {
+		return nil
+	}
+
+	var keys []string
+	for key, v := range newValues {
+		if old, ok := oldValues[key]; !ok || !reflect.DeepEqual(old, v) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range oldValues {
+		if _, ok := newValues[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
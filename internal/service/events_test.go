@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/repository"
+)
+
+func TestConfigService_ChangeStream(t *testing.T) {
+	originDir := t.TempDir()
+	origin := newTestRepo(t, originDir)
+	commit := func(name, content string) {
+		origin.commit("update "+name, time.Now(), map[string]string{name: content})
+	}
+	commit("prod/orders-prod.yaml", "timeout: 5\nretries: 1\n")
+
+	localDir := t.TempDir()
+	cloneTestRepo(t, originDir, filepath.Join(localDir, "main"), 0)
+
+	cfg := &config.Config{RepoPath: localDir, DefaultBranch: "main"}
+	cs := NewConfigServiceFromRepo(repository.NewGitRepo(localDir, originDir), cfg)
+	selections := []Selection{{App: "orders", Env: "prod"}}
+	push := func(name, content string) {
+		commit(name, content)
+		if err := cs.UpdateRepo("main"); err != nil {
+			t.Fatalf("failed to update repo: %v", err)
+		}
+	}
+	next := func(stream *ChangeStream) ([]string, bool) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		events, ok := stream.Next(ctx)
+		if !ok {
+			t.Fatal("stream was not woken by the pull")
+		}
+		if len(events) == 0 {
+			return nil, false
+		}
+		return events[0].ChangedKeys, true
+	}
+
+	stream, missed := cs.NewChangeStream(selections, "", LoadOptions{})
+	if len(missed) != 0 {
+		t.Errorf("expected nothing missed on a fresh stream, got %+v", missed)
+	}
+	configs := stream.Configs()
+	if len(configs) != 1 || configs[0].Label != "main" || configs[0].Version == "" {
+		t.Errorf("unexpected configs %+v", configs)
+	}
+	cursor := stream.Cursor()
+	if cursor == "" {
+		t.Fatal("expected a cursor for a git label")
+	}
+
+	t.Run("Unrelated pull", func(t *testing.T) {
+		push("prod/billing-prod.yaml", "currency: IDR\n")
+		if keys, changed := next(stream); changed {
+			t.Errorf("expected no event, got %v", keys)
+		}
+	})
+
+	t.Run("Changed keys", func(t *testing.T) {
+		push("prod/orders-prod.yaml", "timeout: 10\nregion: eu\n")
+		keys, changed := next(stream)
+		if want := []string{"region", "retries", "timeout"}; !changed || !reflect.DeepEqual(keys, want) {
+			t.Errorf("expected %v, got %v", want, keys)
+		}
+		if stream.Cursor() == cursor {
+			t.Error("expected the cursor to move")
+		}
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		_, missed := cs.NewChangeStream(selections, cursor, LoadOptions{})
+		if len(missed) != 1 || !reflect.DeepEqual(missed[0].ChangedKeys, []string{"region", "retries", "timeout"}) {
+			t.Errorf("expected the missed change, got %+v", missed)
+		}
+
+		_, missed = cs.NewChangeStream(selections, stream.Cursor(), LoadOptions{})
+		if len(missed) != 0 {
+			t.Errorf("expected nothing missed at the current cursor, got %+v", missed)
+		}
+
+		_, missed = cs.NewChangeStream(selections, "main=unknown", LoadOptions{})
+		if len(missed) != 1 || !missed[0].Reset || !reflect.DeepEqual(missed[0].ChangedKeys, []string{"region", "timeout"}) {
+			t.Errorf("expected an unknown commit to reset every key, got %+v", missed)
+		}
+
+		_, missed = cs.NewChangeStream(selections, "%zz", LoadOptions{})
+		if len(missed) != 0 {
+			t.Errorf("expected a malformed cursor to start afresh, got %+v", missed)
+		}
+	})
+
+	t.Run("Context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, ok := stream.Next(ctx); ok {
+			t.Error("expected Next to stop with the context")
+		}
+	})
+}
+
+func TestConfigService_ChangeStream_Shallow(t *testing.T) {
+	originDir := t.TempDir()
+	origin := newTestRepo(t, originDir)
+	old := origin.commit("first", time.Now().Add(-time.Minute), map[string]string{"prod/orders-prod.yaml": "timeout: 5\n"})
+	origin.commit("second", time.Now(), map[string]string{"prod/orders-prod.yaml": "timeout: 10\nregion: eu\n"})
+
+	localDir := t.TempDir()
+	cloneTestRepo(t, originDir, filepath.Join(localDir, "main"), 1)
+
+	cfg := &config.Config{RepoPath: localDir, DefaultBranch: "main"}
+	cs := NewConfigServiceFromRepo(repository.NewGitRepo(localDir, originDir), cfg)
+
+	// the first commit is past the clone depth and resuming does not fetch it
+	_, missed := cs.NewChangeStream([]Selection{{App: "orders", Env: "prod"}}, "main="+old.String(), LoadOptions{})
+	if len(missed) != 1 || !missed[0].Reset || !reflect.DeepEqual(missed[0].ChangedKeys, []string{"region", "timeout"}) {
+		t.Errorf("expected a cursor older than the clone depth to reset every key, got %+v", missed)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect service
+ * https://github.com/KAnggara75/conflect/tree/main/internal/service
+ */
+
+package service
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/errors"
+	"github.com/KAnggara75/conflect/internal/repository"
+)
+
+// DefaultHistoryLimit is the number of commits ConfigHistory returns when no limit
+// is given.
+const DefaultHistoryLimit = 20
+
+// maxHistoryScan bounds the commits ConfigHistory examines, as commits touching the
+// matching files may leave the effective config alone (e.g. an overridden key in
+// application.yml) and each one costs a config load.
+var maxHistoryScan = 500
+
+// ConfigHistory lists up to limit commits on label that changed the effective config
+// of appName for env, newest first, with the keys each one added, removed or changed.
+// Values of secret keys are masked. At most maxHistoryScan matching commits are
+// examined; Truncated is set when the walk stopped there.
+func (c *ConfigService) ConfigHistory(appName, env, label string, limit int, opts LoadOptions) (*dto.HistoryResponse, error) {
+	profiles := splitProfiles(env)
+	if label == "" {
+		label = c.cfg.DefaultBranch
+	}
+	if !isSafePathComponent(appName) || !isSafePathComponent(label) || len(profiles) == 0 {
+		return nil, fmt.Errorf("%w: app %q, env %q, label %q", errors.ErrInvalidInput, appName, env, label)
+	}
+	for _, profile := range profiles {
+		if !isSafePathComponent(profile) {
+			return nil, fmt.Errorf("%w: app %q, env %q, label %q", errors.ErrInvalidInput, appName, env, label)
+		}
+	}
+
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+
+	if err := c.repo.FetchHistory(label); err != nil {
+		log.Println(err)
+	}
+
+	history := &dto.HistoryResponse{
+		Name:     appName,
+		Profiles: profiles,
+		Label:    label,
+		Commits:  []dto.HistoryEntry{},
+	}
+
+	// entries only show key changes, so the lastCommit walk is not needed
+	opts.skipLastCommit = true
+
+	// merged caches the config per commit, since a commit's parent is often the next
+	// commit of the walk
+	merged := make(map[string]*loadedConfig)
+	configAt := func(commit string) *loadedConfig {
+		if loaded, ok := merged[commit]; ok {
+			return loaded
+		}
+		loaded := c.loadAt(appName, env, label, Revision{Commit: commit}, opts)
+		merged[commit] = loaded
+		return loaded
+	}
+
+	var (
+		walkErr error
+		scanned int
+	)
+	err := c.repo.WalkCommits(label, configFileMatcher(appName, profiles), func(commit repository.CommitInfo) bool {
+		if scanned == maxHistoryScan {
+			history.Truncated = true
+			return false
+		}
+		scanned++
+
+		entry := dto.HistoryEntry{
+			Commit:    commit.Hash,
+			Author:    commit.Author,
+			Email:     commit.Email,
+			Timestamp: commit.When,
+			Message:   commit.Message,
+			Added:     make(map[string]any),
+			Removed:   make(map[string]any),
+			Changed:   make(map[string]dto.ValueChange),
+		}
+
+		after := configAt(commit.Hash)
+		delete(merged, commit.Hash) // later commits of the walk are older
+		before := &loadedConfig{response: &dto.ConfigResponse{}}
+		if commit.Parent != "" {
+			before = configAt(commit.Parent)
+		}
+		for _, side := range []*loadedConfig{before, after} {
+			if side.err != nil {
+				walkErr = side.err
+				return false
+			}
+			if side.response.Error != "" {
+				entry.Error = side.response.Error
+			}
+		}
+
+		if entry.Error == "" {
+			c.diffValues(MergeSources(before.response.PropertySources), MergeSources(after.response.PropertySources), entry.Added, entry.Removed, entry.Changed)
+			if len(entry.Added)+len(entry.Removed)+len(entry.Changed) == 0 {
+				// a matching file outside the search paths, or a change that is overridden
+				return true
+			}
+		}
+
+		history.Commits = append(history.Commits, entry)
+		return len(history.Commits) < limit
+	})
+	if err == nil {
+		err = walkErr
+	}
+	if err != nil {
+		return nil, revisionError(err)
+	}
+
+	return history, nil
+}
+
+// configFileMatcher reports whether a path may hold config for appName and profiles,
+// whatever directory it is in. It is a cheap pre-filter for the history walk; the
+// search paths decide which of these files are actually read.
+func configFileMatcher(appName string, profiles []string) func(string) bool {
+	stems := []string{appName, "application"}
+	for _, profile := range profiles {
+		stems = append(stems, appName+"-"+profile, "application-"+profile)
+	}
+
+	return func(p string) bool {
+		name := path.Base(p)
+		ext := path.Ext(name)
+		return slices.Contains(configExtensions, ext) && slices.Contains(stems, strings.TrimSuffix(name, ext))
+	}
+}
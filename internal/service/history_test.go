@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package service
+
+import (
+	stderrors "errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/errors"
+	"github.com/KAnggara75/conflect/internal/repository"
+)
+
+func TestConfigService_ConfigHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := newTestRepo(t, filepath.Join(tmpDir, "main"))
+
+	base := time.Date(2025, 9, 1, 14, 0, 0, 0, time.UTC)
+	n := 0
+	commit := func(author, msg string, files map[string]string) string {
+		n++
+		return repo.commitAs(author, msg, base.Add(time.Duration(n)*time.Minute), files).String()
+	}
+
+	first := commit("alice", "add orders", map[string]string{
+		"prod/orders-prod.yaml": "db:\n  host: a\n  password: one\n",
+	})
+	commit("bob", "unrelated app", map[string]string{
+		"prod/billing-prod.yaml": "currency: IDR\n",
+	})
+	commit("bob", "overridden default", map[string]string{
+		"prod/application.yaml": "db:\n  host: default\n",
+	})
+	rotate := commit("carol", "rotate password\n\nticket OPS-1", map[string]string{
+		"prod/orders-prod.yaml": "db:\n  host: a\n  password: two\ntimeout: 5\n",
+	})
+	remove := commit("dave", "drop override", map[string]string{
+		"prod/orders-prod.yaml": "",
+	})
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+
+	history, err := cs.ConfigHistory("orders", "prod", "", 0, LoadOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if history.Label != "main" {
+		t.Errorf("expected default label main, got %q", history.Label)
+	}
+
+	var commits []string
+	for _, entry := range history.Commits {
+		commits = append(commits, entry.Commit)
+	}
+	if want := []string{remove, rotate, first}; !reflect.DeepEqual(commits, want) {
+		t.Fatalf("commits = %v, want %v", commits, want)
+	}
+
+	removed := history.Commits[0]
+	if want := map[string]any{"db.password": MaskedValue, "timeout": 5}; !reflect.DeepEqual(removed.Removed, want) {
+		t.Errorf("removed = %v, want %v", removed.Removed, want)
+	}
+	if want := map[string]dto.ValueChange{"db.host": {From: "a", To: "default"}}; !reflect.DeepEqual(removed.Changed, want) {
+		t.Errorf("changed = %v, want %v", removed.Changed, want)
+	}
+
+	rotated := history.Commits[1]
+	if rotated.Author != "carol" || rotated.Email != "carol@example.com" || rotated.Message != "rotate password\n\nticket OPS-1" || !rotated.Timestamp.Equal(base.Add(4*time.Minute)) {
+		t.Errorf("unexpected commit details %+v", rotated)
+	}
+	if want := map[string]dto.ValueChange{"db.password": {From: MaskedValue, To: MaskedValue}}; !reflect.DeepEqual(rotated.Changed, want) {
+		t.Errorf("changed = %v, want %v", rotated.Changed, want)
+	}
+
+	added := history.Commits[2]
+	if want := map[string]any{"db.host": "a", "db.password": MaskedValue}; !reflect.DeepEqual(added.Added, want) {
+		t.Errorf("added = %v, want %v", added.Added, want)
+	}
+
+	t.Run("Limit", func(t *testing.T) {
+		history, err := cs.ConfigHistory("orders", "prod", "main", 1, LoadOptions{})
+		if err != nil || len(history.Commits) != 1 || history.Commits[0].Commit != remove {
+			t.Errorf("expected only the newest commit, got %+v (err %v)", history, err)
+		}
+	})
+
+	t.Run("Scan limit", func(t *testing.T) {
+		scan := maxHistoryScan
+		maxHistoryScan = 3
+		defer func() { maxHistoryScan = scan }()
+
+		// the third commit examined only touches an overridden default
+		history, err := cs.ConfigHistory("orders", "prod", "main", 0, LoadOptions{})
+		if err != nil || len(history.Commits) != 2 || !history.Truncated {
+			t.Errorf("expected two commits and a truncated walk, got %+v (err %v)", history, err)
+		}
+	})
+
+	t.Run("Invalid input", func(t *testing.T) {
+		if _, err := cs.ConfigHistory("../orders", "prod", "main", 0, LoadOptions{}); !stderrors.Is(err, errors.ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+
+	t.Run("Unknown label", func(t *testing.T) {
+		if _, err := cs.ConfigHistory("orders", "prod", "missing", 0, LoadOptions{}); !stderrors.Is(err, errors.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect service
+ * https://github.com/KAnggara75/conflect/tree/main/internal/service
+ */
+
+package service
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxLastCommits bounds the lastCommit cache. It is cleared when full, which only
+// costs a log walk per config on the next requests.
+const maxLastCommits = 4096
+
+// lastCommitCache remembers the last commit that touched a set of files, as of a
+// commit. Both are immutable, so entries only go stale when the history of a shallow
+// clone is fetched later, and then name the clone's oldest commit rather than an older
+// one. The zero value is ready to use.
+type lastCommitCache struct {
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+// lastCommit returns the most recent commit on label at or before commit that touched
+// one of paths, walking the log only the first time the combination is seen.
+func (c *ConfigService) lastCommit(label, commit string, paths []string) (string, error) {
+	if commit == "" || len(paths) == 0 {
+		return c.repo.LastCommitForPathsAt(label, commit, paths)
+	}
+
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+	key := label + "\x00" + commit + "\x00" + strings.Join(sorted, "\x00")
+
+	cache := &c.lastCommits
+	cache.mu.Lock()
+	last, ok := cache.entries[key]
+	cache.mu.Unlock()
+	if ok {
+		return last, nil
+	}
+
+	last, err := c.repo.LastCommitForPathsAt(label, commit, paths)
+	if err != nil {
+		return "", err
+	}
+
+	cache.mu.Lock()
+	if cache.entries == nil || len(cache.entries) >= maxLastCommits {
+		cache.entries = make(map[string]string)
+	}
+	cache.entries[key] = last
+	cache.mu.Unlock()
+	return last, nil
+}
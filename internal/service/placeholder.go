@@ -0,0 +1,243 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect service
+ * https://github.com/KAnggara75/conflect/tree/main/internal/service
+ */
+
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+)
+
+// placeholderResolver expands ${key} and ${key:default} references against the
+// merged property sources, where the first source defining a key wins.
+type placeholderResolver struct {
+	sources   []dto.PropertySource
+	fail      bool
+	resolved  map[string]any
+	resolving []string
+	// cycles counts the cycles met so far; a value that met one is not cached, as
+	// the text left for the cycle depends on where its resolution started
+	cycles int
+}
+
+// placeholderCycleError reports a chain of keys that reference each other.
+type placeholderCycleError struct {
+	chain []string
+}
+
+func (e *placeholderCycleError) Error() string {
+	return "placeholder cycle: " + strings.Join(e.chain, " -> ")
+}
+
+// resolvePlaceholders returns a copy of sources with placeholders in string values
+// expanded, including strings nested in lists and maps. A value that is a single
+// placeholder keeps the type of the referenced value. Unresolvable placeholders and
+// cycles are left as written unless fail is set, in which case the first one is
+// returned as an error.
+func resolvePlaceholders(sources []dto.PropertySource, fail bool) ([]dto.PropertySource, error) {
+	r := &placeholderResolver{
+		sources:  sources,
+		fail:     fail,
+		resolved: make(map[string]any),
+	}
+
+	out := make([]dto.PropertySource, len(sources))
+	for i, ps := range sources {
+		source := make(map[string]any, len(ps.Source))
+		for key, value := range ps.Source {
+			var err error
+			if r.owner(key) == i {
+				// the winning value is shared with references from other keys
+				source[key], _, err = r.lookup(key)
+			} else {
+				source[key], err = r.expand(key, value)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		out[i] = dto.PropertySource{Name: ps.Name, Source: source}
+	}
+	return out, nil
+}
+
+// owner returns the index of the source whose value for key wins, or -1.
+func (r *placeholderResolver) owner(key string) int {
+	for i, ps := range r.sources {
+		if _, ok := ps.Source[key]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// lookup returns the resolved merged value of key.
+func (r *placeholderResolver) lookup(key string) (any, bool, error) {
+	if v, ok := r.resolved[key]; ok {
+		return v, true, nil
+	}
+
+	for i, k := range r.resolving {
+		if k == key {
+			chain := append(append([]string{}, r.resolving[i:]...), key)
+			return nil, false, &placeholderCycleError{chain: chain}
+		}
+	}
+
+	idx := r.owner(key)
+	if idx < 0 {
+		return nil, false, nil
+	}
+
+	cycles := r.cycles
+	r.resolving = append(r.resolving, key)
+	v, err := r.expand(key, r.sources[idx].Source[key])
+	r.resolving = r.resolving[:len(r.resolving)-1]
+	if err != nil {
+		return nil, false, err
+	}
+
+	if r.cycles == cycles {
+		r.resolved[key] = v
+	}
+	return v, true, nil
+}
+
+// expand resolves the placeholders in value, which belongs to key.
+func (r *placeholderResolver) expand(key string, value any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return r.expandString(key, v)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			expanded, err := r.expand(key, item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = expanded
+		}
+		return out, nil
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			expanded, err := r.expand(key, item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = expanded
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
+}
+
+func (r *placeholderResolver) expandString(key, s string) (any, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		start := strings.Index(s[i:], "${")
+		if start < 0 {
+			b.WriteString(s[i:])
+			break
+		}
+		start += i
+		end := placeholderEnd(s, start)
+		if end < 0 {
+			// unterminated, keep the rest as written
+			b.WriteString(s[i:])
+			break
+		}
+		b.WriteString(s[i:start])
+
+		value, err := r.resolvePlaceholder(key, s[start:end+1])
+		if err != nil {
+			return nil, err
+		}
+		if start == 0 && end == len(s)-1 {
+			return value, nil
+		}
+		b.WriteString(fmt.Sprint(value))
+		i = end + 1
+	}
+	return b.String(), nil
+}
+
+// resolvePlaceholder resolves one ${name} or ${name:default} reference found in the
+// value of key.
+func (r *placeholderResolver) resolvePlaceholder(key, placeholder string) (any, error) {
+	inner := placeholder[2 : len(placeholder)-1]
+	name, def, hasDefault := cutDefault(inner)
+
+	value, ok, err := r.lookup(name)
+	if err != nil {
+		cycle, isCycle := err.(*placeholderCycleError)
+		if !isCycle || r.fail {
+			return nil, err
+		}
+		log.Printf("leaving %s in %s unresolved: %v", placeholder, key, cycle)
+		r.cycles++
+		return placeholder, nil
+	}
+	if ok {
+		return value, nil
+	}
+	if hasDefault {
+		return r.expandString(key, def)
+	}
+	if r.fail {
+		return nil, fmt.Errorf("unresolved placeholder %s in %s", placeholder, key)
+	}
+	return placeholder, nil
+}
+
+// placeholderEnd returns the index of the brace closing the placeholder at start,
+// allowing nested placeholders in defaults, or -1.
+func placeholderEnd(s string, start int) int {
+	depth := 0
+	for i := start + 2; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "${"):
+			depth++
+			i++
+		case s[i] == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// cutDefault splits name:default at the first colon outside nested placeholders.
+func cutDefault(inner string) (name, def string, ok bool) {
+	depth := 0
+	for i := 0; i < len(inner); i++ {
+		switch {
+		case strings.HasPrefix(inner[i:], "${"):
+			depth++
+			i++
+		case inner[i] == '}':
+			depth--
+		case inner[i] == ':' && depth == 0:
+			return inner[:i], inner[i+1:], true
+		}
+	}
+	return inner, "", false
+}
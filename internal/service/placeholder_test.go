@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/repository"
+)
+
+func TestResolvePlaceholders(t *testing.T) {
+	sources := []dto.PropertySource{
+		{Name: "prod", Source: map[string]any{
+			"db.host": "prod-db",
+			"db.url":  "jdbc:postgresql://${db.host}:${db.port}/app",
+		}},
+		{Name: "default", Source: map[string]any{
+			"db.host":  "localhost",
+			"db.port":  5432,
+			"port":     "${db.port}",
+			"timeout":  "${db.timeout:30}s",
+			"fallback": "${missing:${db.host}}",
+			"hosts":    []any{"${db.host}", map[string]any{"name": "${db.host}"}},
+			"missing2": "x-${nope}-y",
+			"literal":  "cost: $5 {braces} ${unterminated",
+		}},
+	}
+
+	got, err := resolvePlaceholders(sources, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	prod, def := got[0].Source, got[1].Source
+	want := map[string]any{
+		"db.url": "jdbc:postgresql://prod-db:5432/app",
+	}
+	for k, v := range want {
+		if prod[k] != v {
+			t.Errorf("%s = %v, want %v", k, prod[k], v)
+		}
+	}
+
+	want = map[string]any{
+		"db.host":  "localhost",
+		"port":     5432,
+		"timeout":  "30s",
+		"fallback": "prod-db",
+		"missing2": "x-${nope}-y",
+		"literal":  "cost: $5 {braces} ${unterminated",
+	}
+	for k, v := range want {
+		if def[k] != v {
+			t.Errorf("%s = %#v, want %#v", k, def[k], v)
+		}
+	}
+	if hosts := []any{"prod-db", map[string]any{"name": "prod-db"}}; !reflect.DeepEqual(def["hosts"], hosts) {
+		t.Errorf("hosts = %v, want %v", def["hosts"], hosts)
+	}
+
+	if sources[0].Source["db.url"] != "jdbc:postgresql://${db.host}:${db.port}/app" {
+		t.Error("expected input sources to be left untouched")
+	}
+}
+
+func TestResolvePlaceholders_Cycle(t *testing.T) {
+	sources := []dto.PropertySource{
+		{Name: "app", Source: map[string]any{
+			"a":    "${b}",
+			"b":    "x${c}",
+			"c":    "${a}",
+			"self": "${self}",
+		}},
+	}
+
+	if _, err := resolvePlaceholders(sources, true); err == nil || !strings.Contains(err.Error(), "placeholder cycle:") {
+		t.Fatalf("expected cycle error, got %v", err)
+	} else if !strings.Contains(err.Error(), " -> ") {
+		t.Errorf("expected key chain in error, got %v", err)
+	}
+
+	got, err := resolvePlaceholders(sources, false)
+	if err != nil {
+		t.Fatalf("unexpected error in leave mode: %v", err)
+	}
+	if got[0].Source["self"] != "${self}" {
+		t.Errorf("expected self reference left as written, got %v", got[0].Source["self"])
+	}
+
+	// every key of a cycle is unwound from itself, whatever the map order
+	want := map[string]any{"a": "x${a}", "b": "x${b}", "c": "x${c}", "self": "${self}"}
+	for i := 0; i < 50; i++ {
+		got, err := resolvePlaceholders(sources, false)
+		if err != nil {
+			t.Fatalf("unexpected error in leave mode: %v", err)
+		}
+		if !reflect.DeepEqual(got[0].Source, want) {
+			t.Fatalf("run %d: expected %v, got %v", i, want, got[0].Source)
+		}
+	}
+}
+
+func TestResolvePlaceholders_Fail(t *testing.T) {
+	sources := []dto.PropertySource{
+		{Name: "app", Source: map[string]any{"url": "http://${host}"}},
+	}
+
+	_, err := resolvePlaceholders(sources, true)
+	if err == nil || err.Error() != "unresolved placeholder ${host} in url" {
+		t.Errorf("expected unresolved placeholder error, got %v", err)
+	}
+}
+
+func TestConfigService_LoadConfig_Placeholders(t *testing.T) {
+	tmpDir := t.TempDir()
+	envDir := filepath.Join(tmpDir, "main", "prod")
+	_ = os.MkdirAll(envDir, 0755)
+	_ = os.WriteFile(filepath.Join(envDir, "myapp-prod.yaml"), []byte("db:\n  host: prod-db\n"), 0644)
+	_ = os.WriteFile(filepath.Join(envDir, "application.yaml"), []byte("db:\n  host: localhost\n  url: jdbc://${db.host}/${db.name}\n"), 0644)
+
+	repo := repository.NewGitRepo(tmpDir, "")
+
+	cs := NewConfigServiceFromRepo(repo, &config.Config{RepoPath: tmpDir, DefaultBranch: "main"})
+	resp := cs.LoadConfig("myapp", "prod", "main")
+	if got := resp.PropertySources[1].Source["db.url"]; got != "jdbc://${db.host}/${db.name}" {
+		t.Errorf("expected placeholders kept when resolution is off, got %v", got)
+	}
+
+	cs = NewConfigServiceFromRepo(repo, &config.Config{RepoPath: tmpDir, DefaultBranch: "main", Placeholders: config.PlaceholdersLeave})
+	resp = cs.LoadConfig("myapp", "prod", "main")
+	if got := resp.PropertySources[1].Source["db.url"]; got != "jdbc://prod-db/${db.name}" {
+		t.Errorf("expected resolved url, got %v", got)
+	}
+
+	cs = NewConfigServiceFromRepo(repo, &config.Config{RepoPath: tmpDir, DefaultBranch: "main", Placeholders: config.PlaceholdersFail})
+	resp = cs.LoadConfig("myapp", "prod", "main")
+	if len(resp.PropertySources) != 0 || !strings.Contains(resp.Error, "${db.name}") {
+		t.Errorf("expected failure naming the placeholder, got %+v", resp)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect service
+ * https://github.com/KAnggara75/conflect/tree/main/internal/service
+ */
+
+package service
+
+import (
+	"strings"
+
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+)
+
+// LookupProperties returns the effective value of key for appName/env/label, with the
+// same precedence as LoadConfig. With prefix set it returns every key equal to key or
+// nested under it (key.* and key[*]). Properties is empty when nothing matches.
+func (c *ConfigService) LookupProperties(appName, env, label, key string, prefix bool, opts LoadOptions) *dto.PropertyResponse {
+	resp := c.LoadConfigWithOptions(appName, env, label, opts)
+
+	out := &dto.PropertyResponse{
+		Name:       resp.Name,
+		Profiles:   resp.Profiles,
+		Label:      resp.Label,
+		Version:    resp.Version,
+		Properties: make(map[string]dto.PropertyValue),
+		Error:      resp.Error,
+	}
+
+	for _, ps := range resp.PropertySources {
+		for k, v := range ps.Source {
+			if !matchesKey(k, key, prefix) {
+				continue
+			}
+			if _, ok := out.Properties[k]; !ok {
+				out.Properties[k] = dto.PropertyValue{Value: v, Source: ps.Name}
+			}
+		}
+	}
+
+	return out
+}
+
+func matchesKey(k, key string, prefix bool) bool {
+	if k == key {
+		return true
+	}
+	if !prefix || !strings.HasPrefix(k, key) {
+		return false
+	}
+	next := k[len(key)]
+	return next == '.' || next == '['
+}
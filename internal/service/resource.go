@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect service
+ * https://github.com/KAnggara75/conflect/tree/main/internal/service
+ */
+
+package service
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/KAnggara75/conflect/internal/errors"
+)
+
+// LoadResource returns the raw content of resourcePath for appName/env/label and the
+// path it was found at, relative to the label root. The file is looked up in the same
+// directories as config files, highest priority first: the search paths of each
+// profile (later profiles first), then the shared paths and the branch root. A path
+// never reaches into the search directory of another application or profile. With
+// resolve set, ${key} and ${key:default} references are expanded from the app's
+// resolved properties; unknown references are left as written.
+func (c *ConfigService) LoadResource(appName, env, label, resourcePath string, resolve bool) ([]byte, string, error) {
+	profiles := splitProfiles(env)
+	if label == "" {
+		label = c.cfg.DefaultBranch
+	}
+
+	if !isSafePathComponent(appName) || !isSafePathComponent(label) || len(profiles) == 0 {
+		return nil, "", fmt.Errorf("%w: app %q, env %q, label %q", errors.ErrInvalidInput, appName, env, label)
+	}
+	for _, profile := range profiles {
+		if !isSafePathComponent(profile) {
+			return nil, "", fmt.Errorf("%w: profile %q", errors.ErrInvalidInput, profile)
+		}
+	}
+	if !isSafeResourcePath(resourcePath) {
+		return nil, "", fmt.Errorf("%w: resource path %q", errors.ErrInvalidInput, resourcePath)
+	}
+	if isForeignConfigFile(resourcePath, appName, profiles) {
+		return nil, "", fmt.Errorf("%w: resource %q is not a config file of %s with env %s", errors.ErrInvalidInput, resourcePath, appName, env)
+	}
+
+	root, err := filepath.EvalSymlinks(filepath.Join(c.repo.Path, label))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: label %s", errors.ErrNotFound, label)
+	}
+
+	dirs, err := c.searchLocations(os.DirFS(root), appName, profiles, label)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, dir := range dirs {
+		found := path.Join(dir, resourcePath)
+		if c.entersOtherLocation(found, label, dirs) {
+			continue
+		}
+		data, err := readInside(root, found)
+		if err != nil {
+			return nil, "", err
+		}
+		if data == nil {
+			continue
+		}
+
+		if resolve {
+			data, err = c.substituteProperties(appName, env, label, data)
+			if err != nil {
+				return nil, "", err
+			}
+		}
+		return data, found, nil
+	}
+
+	return nil, "", fmt.Errorf("%w: resource %s", errors.ErrNotFound, resourcePath)
+}
+
+// searchLocations lists the directories searched for appName, highest priority first:
+// the search paths of each profile in reverse, then the shared paths and the root.
+func (c *ConfigService) searchLocations(fsys fs.FS, appName string, profiles []string, label string) ([]string, error) {
+	var locations []string
+	for i := len(profiles) - 1; i >= 0; i-- {
+		dirs, err := c.searchDirs(fsys, label, c.searchPaths(), appName, profiles[i])
+		if err != nil {
+			return nil, err
+		}
+		locations = append(locations, dirs...)
+	}
+
+	shared, err := c.searchDirs(fsys, label, append(append([]string{}, c.cfg.SharedPaths...), ""), appName, "")
+	if err != nil {
+		return nil, err
+	}
+	return uniqueStrings(append(locations, shared...)), nil
+}
+
+// entersOtherLocation reports whether found lies below a directory that a search-path
+// or shared-path template yields for some application or profile, other than the
+// directories in allowed. From the branch root, staging/db-password.txt would
+// otherwise serve the files of the staging profile to a token scoped to prod.
+func (c *ConfigService) entersOtherLocation(found, label string, allowed []string) bool {
+	anyName := strings.NewReplacer("{application}", "*", "{profile}", "*", "{label}", globEscaper.Replace(label))
+	templates := append(append([]string{}, c.searchPaths()...), c.cfg.SharedPaths...)
+	for dir := path.Dir(found); dir != "."; dir = path.Dir(dir) {
+		if slices.Contains(allowed, dir) {
+			continue
+		}
+		for _, tmpl := range templates {
+			pattern := anyName.Replace(strings.Trim(strings.TrimSpace(tmpl), "/"))
+			if ok, _ := path.Match(pattern, dir); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isForeignConfigFile reports whether resourcePath names a config file that does not
+// belong to appName and profiles, such as billing-prod.yaml requested as a resource of
+// orders. Those files sit next to the app's own in the search directories, and serving
+// them would get round access scopes, which are checked against the app in the URL.
+func isForeignConfigFile(resourcePath, appName string, profiles []string) bool {
+	name := path.Base(resourcePath)
+	ext := path.Ext(name)
+	if !slices.Contains(configExtensions, ext) {
+		return false
+	}
+
+	stem := strings.TrimSuffix(name, ext)
+	if stem == appName || stem == "application" {
+		return false
+	}
+	for _, profile := range profiles {
+		if stem == appName+"-"+profile || stem == "application-"+profile {
+			return false
+		}
+	}
+	return true
+}
+
+// isSafeResourcePath accepts slash-separated relative paths without empty, dot,
+// parent or hidden segments, so .git and friends cannot be served.
+func isSafeResourcePath(p string) bool {
+	if p == "" || strings.Contains(p, `\`) || strings.ContainsRune(p, 0) {
+		return false
+	}
+	for _, segment := range strings.Split(p, "/") {
+		if segment == "" || strings.HasPrefix(segment, ".") {
+			return false
+		}
+	}
+	return true
+}
+
+// readInside reads the regular file rel under root, following symlinks only while
+// they stay inside root. It returns nil data when the file does not exist.
+func readInside(root, rel string) ([]byte, error) {
+	resolved, err := filepath.EvalSymlinks(filepath.Join(root, filepath.FromSlash(rel)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to resolve %s: %w", rel, err)
+	}
+
+	inside, err := filepath.Rel(root, resolved)
+	if err != nil || inside == ".." || strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%w: %s points outside the repository", errors.ErrInvalidInput, rel)
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", rel, err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	return data, nil
+}
+
+// substituteProperties expands placeholders in data from the resolved config of
+// appName/env/label, leaving unknown references and cycles as written.
+func (c *ConfigService) substituteProperties(appName, env, label string, data []byte) ([]byte, error) {
+	resp := c.load(appName, env, label, LoadOptions{}).response
+	if resp.Error != "" {
+		return nil, fmt.Errorf("%w: %s", errors.ErrInvalidInput, resp.Error)
+	}
+
+	r := &placeholderResolver{sources: resp.PropertySources, resolved: make(map[string]any)}
+	out, err := r.expandString("resource", string(data))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(fmt.Sprint(out)), nil
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package service
+
+import (
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/errors"
+	"github.com/KAnggara75/conflect/internal/repository"
+)
+
+func TestConfigService_LoadResource(t *testing.T) {
+	tmpDir := t.TempDir()
+	mainDir := filepath.Join(tmpDir, "main")
+	for _, f := range []struct{ name, content string }{
+		{"prod/nginx/site.conf", "listen ${server.port};\nroot ${docroot:/var/www};\nset $x ${unknown};\n"},
+		{"prod/myapp-prod.yaml", "server:\n  port: 80\n"},
+		{"logback.xml", "<configuration/>"},
+		{"prod/logback.xml", "<configuration debug=\"true\"/>"},
+		{".git/config", "[core]"},
+		{"staging/db-password.txt", "hunter2"},
+		{"billing/keystore.pem", "key"},
+	} {
+		full := filepath.Join(mainDir, filepath.FromSlash(f.name))
+		_ = os.MkdirAll(filepath.Dir(full), 0755)
+		_ = os.WriteFile(full, []byte(f.content), 0644)
+	}
+	secret := filepath.Join(tmpDir, "secret.txt")
+	_ = os.WriteFile(secret, []byte("secret"), 0644)
+	_ = os.Symlink(secret, filepath.Join(mainDir, "prod", "escape.txt"))
+
+	cfg := &config.Config{RepoPath: tmpDir, DefaultBranch: "main"}
+	cs := NewConfigServiceFromRepo(repository.NewGitRepo(tmpDir, ""), cfg)
+
+	data, found, err := cs.LoadResource("myapp", "prod", "main", "logback.xml", false)
+	if err != nil || found != "prod/logback.xml" || string(data) != `<configuration debug="true"/>` {
+		t.Errorf("expected profile logback.xml, got %q from %q (err %v)", data, found, err)
+	}
+
+	data, found, err = cs.LoadResource("myapp", "dev", "", "logback.xml", false)
+	if err != nil || found != "logback.xml" || string(data) != "<configuration/>" {
+		t.Errorf("expected root logback.xml, got %q from %q (err %v)", data, found, err)
+	}
+
+	data, _, err = cs.LoadResource("myapp", "prod", "main", "nginx/site.conf", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "listen 80;\nroot /var/www;\nset $x ${unknown};\n"; string(data) != want {
+		t.Errorf("expected substituted resource %q, got %q", want, data)
+	}
+
+	data, _, _ = cs.LoadResource("myapp", "prod", "main", "nginx/site.conf", false)
+	if string(data) != "listen ${server.port};\nroot ${docroot:/var/www};\nset $x ${unknown};\n" {
+		t.Errorf("expected raw resource without resolve, got %q", data)
+	}
+
+	if _, _, err := cs.LoadResource("myapp", "prod", "main", "missing.txt", false); !stderrors.Is(err, errors.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	for _, p := range []string{"../secret.txt", "nginx/../../secret.txt", ".git/config", "nginx//site.conf", `nginx\site.conf`, "escape.txt"} {
+		if _, _, err := cs.LoadResource("myapp", "prod", "main", p, false); !stderrors.Is(err, errors.ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput for %q, got %v", p, err)
+		}
+	}
+
+	// config files of other apps and profiles are not resources of this app
+	for _, p := range []string{"billing-prod.yaml", "nginx/billing.yml", "myapp-dev.properties", "application-dev.json"} {
+		if _, _, err := cs.LoadResource("myapp", "prod", "main", p, false); !stderrors.Is(err, errors.ErrInvalidInput) {
+			t.Errorf("expected ErrInvalidInput for %q, got %v", p, err)
+		}
+	}
+	if _, found, err := cs.LoadResource("myapp", "prod", "main", "myapp-prod.yaml", false); err != nil || found != "prod/myapp-prod.yaml" {
+		t.Errorf("expected the app's own config file, got %q (err %v)", found, err)
+	}
+
+	// the root does not reach into the directories of other profiles or apps
+	for _, p := range []string{"staging/db-password.txt", "billing/keystore.pem"} {
+		if data, found, err := cs.LoadResource("myapp", "prod", "main", p, false); !stderrors.Is(err, errors.ErrNotFound) {
+			t.Errorf("expected ErrNotFound for %q, got %q from %q (err %v)", p, data, found, err)
+		}
+	}
+	if _, found, err := cs.LoadResource("myapp", "staging", "main", "db-password.txt", false); err != nil || found != "staging/db-password.txt" {
+		t.Errorf("expected the staging profile's own file, got %q (err %v)", found, err)
+	}
+
+	if _, _, err := cs.LoadResource("myapp", "prod", "main", "nginx", false); !stderrors.Is(err, errors.ErrNotFound) {
+		t.Errorf("expected directories to be ErrNotFound, got %v", err)
+	}
+}
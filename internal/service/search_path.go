@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect service
+ * https://github.com/KAnggara75/conflect/tree/main/internal/service
+ */
+
+package service
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/KAnggara75/conflect/internal/config"
+)
+
+// globEscaper escapes glob metacharacters in placeholder values so that only the
+// template itself can contain patterns.
+var globEscaper = strings.NewReplacer(`*`, `\*`, `?`, `\?`, `[`, `\[`, `\`, `\\`)
+
+// searchPaths returns the configured search-path templates, defaulting to one
+// directory per profile.
+func (c *ConfigService) searchPaths() []string {
+	if len(c.cfg.SearchPaths) == 0 {
+		return []string{config.DefaultSearchPath}
+	}
+	return c.cfg.SearchPaths
+}
+
+// searchDirs expands the search-path templates for one application and profile into
+// the existing directories they name, as slash paths relative to the label root ("" is
+// the root itself). Templates may use {application}, {profile} and {label} and glob
+// segments such as `teams/*/{application}`; glob matches are sorted and skip hidden
+// directories. Templates that would leave the branch are ignored, as are templates
+// using {profile} when profile is empty. Directories are returned in template order
+// without duplicates.
+func (c *ConfigService) searchDirs(fsys fs.FS, label string, templates []string, appName, profile string) ([]string, error) {
+	var (
+		dirs []string
+		seen = make(map[string]bool)
+	)
+
+	plain := strings.NewReplacer("{application}", appName, "{profile}", profile, "{label}", label)
+	escaped := strings.NewReplacer(
+		"{application}", globEscaper.Replace(appName),
+		"{profile}", globEscaper.Replace(profile),
+		"{label}", globEscaper.Replace(label),
+	)
+	for _, tmpl := range templates {
+		tmpl = strings.Trim(strings.TrimSpace(tmpl), "/")
+		if !isSafeSearchPath(tmpl) {
+			log.Printf("skip unsafe search path: %q", tmpl)
+			continue
+		}
+		if profile == "" && strings.Contains(tmpl, "{profile}") {
+			log.Printf("skip search path %q: no profile to substitute", tmpl)
+			continue
+		}
+
+		isGlob := strings.ContainsAny(tmpl, "*?[")
+		replacer := plain
+		if isGlob {
+			replacer = escaped
+		}
+
+		pattern := path.Clean(replacer.Replace(tmpl))
+		if pattern == "." {
+			pattern = ""
+		}
+
+		matches := []string{pattern}
+		if isGlob {
+			if pattern == "" {
+				pattern = "."
+			}
+			found, err := fs.Glob(fsys, pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid search path %q: %w", tmpl, err)
+			}
+
+			matches = matches[:0]
+			for _, m := range found {
+				if hasHiddenSegment(m) {
+					continue
+				}
+				if info, err := fs.Stat(fsys, m); err != nil || !info.IsDir() {
+					continue
+				}
+				matches = append(matches, m)
+			}
+		}
+
+		for _, dir := range matches {
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+
+	return dirs, nil
+}
+
+// isSafeSearchPath rejects search-path templates that could escape the branch root.
+func isSafeSearchPath(tmpl string) bool {
+	if strings.Contains(tmpl, `\`) {
+		return false
+	}
+	for _, segment := range strings.Split(tmpl, "/") {
+		if segment == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// hasHiddenSegment reports whether a slash path contains a dot-prefixed segment such
+// as .git.
+func hasHiddenSegment(p string) bool {
+	for _, segment := range strings.Split(p, "/") {
+		if strings.HasPrefix(segment, ".") && segment != "." {
+			return true
+		}
+	}
+	return false
+}
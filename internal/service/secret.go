@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect service
+ * https://github.com/KAnggara75/conflect/tree/main/internal/service
+ */
+
+package service
+
+import (
+	"strings"
+
+	"github.com/KAnggara75/conflect/internal/config"
+)
+
+// MaskedValue replaces secret values in diff and history output.
+const MaskedValue = "******"
+
+// isSecretKey reports whether key contains one of the configured secret fragments.
+// Both sides are compared lower-cased with separators removed, so "apikey" matches
+// api-key, apiKey and API_KEY.
+func (c *ConfigService) isSecretKey(key string) bool {
+	fragments := c.cfg.SecretKeys
+	if len(fragments) == 0 {
+		fragments = config.DefaultSecretKeys
+	}
+
+	normalized := normalizeKey(key)
+	for _, fragment := range fragments {
+		if f := normalizeKey(fragment); f != "" && strings.Contains(normalized, f) {
+			return true
+		}
+	}
+	return false
+}
+
+// mask returns MaskedValue for secret keys and v otherwise. Maps and lists, such as
+// raw arrays of objects, are copied with the values of their secret keys masked, at
+// any depth.
+func (c *ConfigService) mask(key string, v any) any {
+	if c.isSecretKey(key) {
+		return MaskedValue
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		masked := make(map[string]any, len(v))
+		for k, item := range v {
+			masked[k] = c.mask(k, item)
+		}
+		return masked
+	case []any:
+		masked := make([]any, len(v))
+		for i, item := range v {
+			// list items have no key of their own; their nested keys are checked
+			masked[i] = c.mask("", item)
+		}
+		return masked
+	default:
+		return v
+	}
+}
+
+func normalizeKey(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
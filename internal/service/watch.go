@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ *
+ * @author KAnggara75
+ * @project conflect service
+ * https://github.com/KAnggara75/conflect/tree/main/internal/service
+ */
+
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+)
+
+// branchUpdates wakes the requests waiting for a branch to be pulled. The zero value
+// is ready to use.
+type branchUpdates struct {
+	mu      sync.Mutex
+	waiters map[string]chan struct{}
+}
+
+// wait returns a channel that is closed by the next update of branch.
+func (b *branchUpdates) wait(branch string) <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.waiters == nil {
+		b.waiters = make(map[string]chan struct{})
+	}
+	ch, ok := b.waiters[branch]
+	if !ok {
+		ch = make(chan struct{})
+		b.waiters[branch] = ch
+	}
+	return ch
+}
+
+// notify wakes everyone waiting on branch.
+func (b *branchUpdates) notify(branch string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ch, ok := b.waiters[branch]; ok {
+		close(ch)
+		delete(b.waiters, branch)
+	}
+}
+
+// WatchConfig returns the config of appName for env on label as soon as it differs
+// from the one the caller has at version, re-checking each time the branch is pulled.
+// It reports false when ctx ends first. Only the resolved property sources count as a
+// change, so in commit version mode a pull that leaves them alone keeps waiting.
+func (c *ConfigService) WatchConfig(ctx context.Context, appName, env, label, version string, opts LoadOptions) (*dto.ConfigResponse, bool) {
+	if label == "" {
+		label = c.cfg.DefaultBranch
+	}
+
+	// subscribe before loading so an update in between is not missed
+	updated := c.updates.wait(label)
+	resp := c.LoadConfigWithOptions(appName, env, label, opts)
+	if resp.Version != version || resp.Error != "" || len(resp.PropertySources) == 0 {
+		return resp, true
+	}
+	baseline := contentVersion(resp.PropertySources)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return resp, false
+		case <-updated:
+		}
+
+		updated = c.updates.wait(label)
+		next := c.LoadConfigWithOptions(appName, env, label, opts)
+		if next.Error != "" || len(next.PropertySources) == 0 || contentVersion(next.PropertySources) != baseline {
+			return next, true
+		}
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2025 KAnggara75
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * See <https://www.gnu.org/licenses/gpl-3.0.html>.
+ */
+
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KAnggara75/conflect/internal/config"
+	"github.com/KAnggara75/conflect/internal/delivery/http/dto"
+	"github.com/KAnggara75/conflect/internal/repository"
+)
+
+func TestConfigService_WatchConfig(t *testing.T) {
+	originDir := t.TempDir()
+	origin := newTestRepo(t, originDir)
+	commit := func(name, content string) {
+		origin.commit("update "+name, time.Now(), map[string]string{name: content})
+	}
+	commit("prod/orders-prod.yaml", "timeout: 5\n")
+
+	localDir := t.TempDir()
+	cloneTestRepo(t, originDir, filepath.Join(localDir, "main"), 0)
+
+	cfg := &config.Config{RepoPath: localDir, DefaultBranch: "main"}
+	cs := NewConfigServiceFromRepo(repository.NewGitRepo(localDir, originDir), cfg)
+	current := cs.LoadConfig("orders", "prod", "main")
+
+	type result struct {
+		resp    *dto.ConfigResponse
+		changed bool
+	}
+	watch := func(version string, timeout time.Duration) <-chan result {
+		ch := make(chan result, 1)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			resp, changed := cs.WatchConfig(ctx, "orders", "prod", "", version, LoadOptions{})
+			ch <- result{resp, changed}
+		}()
+		return ch
+	}
+
+	t.Run("Stale version", func(t *testing.T) {
+		res := <-watch("old", time.Second)
+		if !res.changed || res.resp.Version != current.Version {
+			t.Errorf("expected the current config at once, got %+v", res)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		res := <-watch(current.Version, 50*time.Millisecond)
+		if res.changed {
+			t.Errorf("expected no change, got %+v", res.resp)
+		}
+	})
+
+	t.Run("Unrelated pull keeps waiting", func(t *testing.T) {
+		pending := watch(current.Version, 300*time.Millisecond)
+		time.Sleep(50 * time.Millisecond)
+		commit("prod/billing-prod.yaml", "currency: IDR\n")
+		if err := cs.UpdateRepo("main"); err != nil {
+			t.Fatalf("failed to update repo: %v", err)
+		}
+		if res := <-pending; res.changed {
+			t.Errorf("expected no change for an unrelated commit, got %+v", res.resp)
+		}
+	})
+
+	t.Run("Woken by pull", func(t *testing.T) {
+		// the unrelated pull moved HEAD, which is the version in commit mode
+		latest := cs.LoadConfig("orders", "prod", "main")
+		if latest.Version == current.Version {
+			t.Fatal("expected the unrelated pull to change the commit version")
+		}
+
+		pending := watch(latest.Version, 5*time.Second)
+		time.Sleep(50 * time.Millisecond)
+		commit("prod/orders-prod.yaml", "timeout: 10\n")
+		if err := cs.UpdateRepo("main"); err != nil {
+			t.Fatalf("failed to update repo: %v", err)
+		}
+
+		select {
+		case res := <-pending:
+			if !res.changed || res.resp.PropertySources[0].Source["timeout"] != 10 {
+				t.Errorf("expected the updated config, got %+v", res.resp)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("watch was not woken by the pull")
+		}
+	})
+}